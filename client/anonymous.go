@@ -0,0 +1,171 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/contactlab/contacthub-sdk-go/enums"
+)
+
+// DefaultAnonymousSessionTTL is the default time an AnonymousTracker keeps counting the events of an idle session
+const DefaultAnonymousSessionTTL = 24 * time.Hour
+
+// AnonymousTracker records the events of anonymous visitors, keyed by their session ID,
+// and reconciles them with a Customer once the visitor is identified
+type AnonymousTracker struct {
+	client *Client
+	ttl    time.Duration
+
+	// mu guards sessions, the event counts by session ID, which are forgotten after ttl without events
+	mu       sync.Mutex
+	sessions map[string]*anonymousSession
+	nextGC   time.Time
+	nowFunc  func() time.Time
+}
+
+type anonymousSession struct {
+	events int
+	expiry time.Time
+}
+
+// ReconcileResult contains the outcome of AnonymousTracker.Reconcile
+type ReconcileResult struct {
+	// Session is the session registered on the customer. When it was already registered, it is looked up
+	// on the customer, and it is nil if it cannot be found there
+	Session *SessionResponse
+	// LinkedEvents is the number of events tracked for the session by this AnonymousTracker
+	LinkedEvents int
+}
+
+// NewAnonymousTracker creates an AnonymousTracker, which forgets the event counts of the sessions
+// idle for ttl, so that the sessions never reconciled do not pile up. Zero means DefaultAnonymousSessionTTL
+func (s *SessionService) NewAnonymousTracker(ttl time.Duration) *AnonymousTracker {
+	if ttl <= 0 {
+		ttl = DefaultAnonymousSessionTTL
+	}
+	return &AnonymousTracker{client: s.client, ttl: ttl, sessions: map[string]*anonymousSession{}, nowFunc: time.Now}
+}
+
+// Track creates an anonymous event, setting its BringBackProperty to the session ID on the default Node
+func (t *AnonymousTracker) Track(sessionID string, event *Event) (*EventResponse, error) {
+	return t.TrackWithContext(context.Background(), sessionID, event)
+}
+
+// TrackWithContext is the context-aware version of Track
+func (t *AnonymousTracker) TrackWithContext(ctx context.Context, sessionID string, event *Event) (*EventResponse, error) {
+	if err := t.Prepare(sessionID, event); err != nil {
+		return nil, err
+	}
+
+	createdEvent, err := t.client.Events.CreateWithContext(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.nowFunc()
+	if now.After(t.nextGC) {
+		// Purge the expired sessions at most once per TTL
+		for id, session := range t.sessions {
+			if !now.Before(session.expiry) {
+				delete(t.sessions, id)
+			}
+		}
+		t.nextGC = now.Add(t.ttl)
+	}
+	session, ok := t.sessions[sessionID]
+	if !ok || !now.Before(session.expiry) {
+		session = &anonymousSession{}
+		t.sessions[sessionID] = session
+	}
+	session.events++
+	session.expiry = now.Add(t.ttl)
+	return createdEvent, nil
+}
+
+// Prepare sets the BringBackProperty of an anonymous event to the session ID on the default Node,
+// for the events sent by other means, e.g. an EventTracker. Those events are not counted in LinkedEvents
+func (t *AnonymousTracker) Prepare(sessionID string, event *Event) error {
+	if sessionID == "" {
+		return errors.New("sessionID is required")
+	}
+	if event.CustomerID != nil && event.CustomerID.Valid {
+		return errors.New("anonymous events cannot have a CustomerID")
+	}
+	event.BringBackProperty = &BringBackProperty{
+		Type:   enums.SessionId,
+		Value:  sessionID,
+		NodeID: t.client.Config.DefaultNodeID,
+	}
+	return nil
+}
+
+// Reconcile registers the session on the customer, so that ContactHub links the anonymous events
+// of the session to it. A session already registered is not an error.
+// The number of linked events only includes the ones tracked by this AnonymousTracker
+func (t *AnonymousTracker) Reconcile(sessionID, customerID string) (*ReconcileResult, error) {
+	return t.ReconcileWithContext(context.Background(), sessionID, customerID)
+}
+
+// ReconcileWithContext is the context-aware version of Reconcile
+func (t *AnonymousTracker) ReconcileWithContext(ctx context.Context, sessionID, customerID string) (*ReconcileResult, error) {
+	session, err := t.client.Sessions.CreateWithContext(ctx, customerID, &Session{Value: sessionID})
+	if IsConflict(err) {
+		session, err = t.client.Sessions.FindByValueWithContext(ctx, customerID, sessionID)
+		if IsNotFound(err) {
+			session, err = nil, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	linked := 0
+	if s, ok := t.sessions[sessionID]; ok && t.nowFunc().Before(s.expiry) {
+		linked = s.events
+	}
+	delete(t.sessions, sessionID)
+	t.mu.Unlock()
+
+	return &ReconcileResult{Session: session, LinkedEvents: linked}, nil
+}
+
+// Forget discards the count of the events of a session which is never going to be reconciled
+func (t *AnonymousTracker) Forget(sessionID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.sessions, sessionID)
+}
+
+// Len returns the number of sessions with counted events, expired ones included until purged
+func (t *AnonymousTracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.sessions)
+}
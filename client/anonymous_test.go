@@ -0,0 +1,163 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/contactlab/contacthub-sdk-go/enums"
+	"github.com/contactlab/contacthub-sdk-go/nullable"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestAnonymousTracker(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		event := new(Event)
+		json.NewDecoder(r.Body).Decode(event)
+
+		expected := &BringBackProperty{Type: enums.SessionId, Value: "my-session-id", NodeID: "fakenodeid"}
+		if diff := pretty.Compare(event.BringBackProperty, expected); diff != "" {
+			t.Errorf("Track: invalid bring back property: (-got +expected)\n%s", diff)
+		}
+		if event.CustomerID != nil {
+			t.Errorf("Expected no customer ID, got %v", event.CustomerID)
+		}
+		fmt.Fprint(w, `{"id":"my-event-id"}`)
+	})
+	mux.HandleFunc("/customers/my-customer-id/sessions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		session := new(Session)
+		json.NewDecoder(r.Body).Decode(session)
+		if session.Value != "my-session-id" {
+			t.Errorf("Expected session value my-session-id, got %v", session.Value)
+		}
+		fmt.Fprint(w, `{"id":"my-session","value":"my-session-id"}`)
+	})
+
+	tracker := testClient.Sessions.NewAnonymousTracker(0)
+	for i := 0; i < 3; i++ {
+		event := &Event{Type: enums.ViewedPage, Context: enums.Web, Properties: map[string]interface{}{}}
+		if _, err := tracker.Track("my-session-id", event); err != nil {
+			t.Fatalf("Unexpected error. Track: %v", err)
+		}
+	}
+
+	result, err := tracker.Reconcile("my-session-id", "my-customer-id")
+	if err != nil {
+		t.Fatalf("Unexpected error. Reconcile: %v", err)
+	}
+	expected := &ReconcileResult{Session: &SessionResponse{ID: "my-session", Value: "my-session-id"}, LinkedEvents: 3}
+	if diff := pretty.Compare(result, expected); diff != "" {
+		t.Errorf("Reconcile: invalid result: (-got +expected)\n%s", diff)
+	}
+
+	result, err = tracker.Reconcile("my-session-id", "my-customer-id")
+	if err != nil || result.LinkedEvents != 0 {
+		t.Errorf("Expected no linked events after the reconciliation, got %v, %v", result, err)
+	}
+}
+
+func TestAnonymousTrackerInvalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request for invalid anonymous events")
+	})
+
+	tracker := testClient.Sessions.NewAnonymousTracker(0)
+	if _, err := tracker.Track("", &Event{}); err == nil {
+		t.Error("Expected error without session ID")
+	}
+	if _, err := tracker.Track("my-session-id", &Event{CustomerID: nullable.StringFrom("my-customer-id")}); err == nil {
+		t.Error("Expected error for an event with a customer ID")
+	}
+}
+
+func TestAnonymousTrackerConflict(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/my-customer-id/sessions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"message":"session already exists"}`)
+			return
+		}
+		fmt.Fprint(w, `[{"id":"my-session","value":"my-session-id"}]`)
+	})
+
+	tracker := testClient.Sessions.NewAnonymousTracker(0)
+	result, err := tracker.Reconcile("my-session-id", "my-customer-id")
+	if err != nil {
+		t.Fatalf("Unexpected error. Reconcile: %v", err)
+	}
+	expected := &ReconcileResult{Session: &SessionResponse{ID: "my-session", Value: "my-session-id"}}
+	if diff := pretty.Compare(result, expected); diff != "" {
+		t.Errorf("Reconcile: invalid result: (-got +expected)\n%s", diff)
+	}
+}
+
+func TestAnonymousTrackerExpiry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"my-event-id"}`)
+	})
+	mux.HandleFunc("/customers/my-customer-id/sessions", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"my-session","value":"session-a"}`)
+	})
+
+	now := time.Now()
+	tracker := testClient.Sessions.NewAnonymousTracker(time.Minute)
+	tracker.nowFunc = func() time.Time { return now }
+	track := func(sessionID string) {
+		event := &Event{Type: enums.ViewedPage, Context: enums.Web, Properties: map[string]interface{}{}}
+		if _, err := tracker.Track(sessionID, event); err != nil {
+			t.Fatalf("Unexpected error. Track: %v", err)
+		}
+	}
+
+	track("session-a")
+	track("session-b")
+	now = now.Add(2 * time.Minute)
+	track("session-c")
+	if tracker.Len() != 1 {
+		t.Errorf("Expected the idle sessions to be purged, got %v sessions", tracker.Len())
+	}
+
+	track("session-a")
+	now = now.Add(30 * time.Second)
+	if result, err := tracker.Reconcile("session-a", "my-customer-id"); err != nil || result.LinkedEvents != 1 {
+		t.Errorf("Expected 1 linked event after the expiry, got %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,115 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchOptions configures CreateBatch. Zero values are replaced with the defaults
+type BatchOptions struct {
+	// Workers is the number of events sent concurrently, DefaultParallelWorkers by default
+	Workers int
+}
+
+// BatchResult is the outcome of a single event of a batch
+type BatchResult struct {
+	// Index is the position of the event in the batch
+	Index int
+	Event *EventResponse
+	Err   error
+}
+
+// BatchReport contains the results of a batch, in the same order as the events
+type BatchReport struct {
+	Results []BatchResult
+}
+
+// Failed returns the results of the events which could not be created
+func (r *BatchReport) Failed() []BatchResult {
+	var failed []BatchResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Succeeded returns the number of events created
+func (r *BatchReport) Succeeded() int {
+	return len(r.Results) - len(r.Failed())
+}
+
+// FailedEvents returns the events of the batch which could not be created, to be retried
+func (r *BatchReport) FailedEvents(events []*Event) []*Event {
+	var failed []*Event
+	for _, result := range r.Failed() {
+		failed = append(failed, events[result.Index])
+	}
+	return failed
+}
+
+// CreateBatch creates many events concurrently. The ContactHub API has no bulk endpoint, so the events are
+// sent one by one by a pool of workers, honouring the client rate limiter.
+// A failure does not stop the batch: the report contains the outcome of every event
+func (s *EventService) CreateBatch(events []*Event, options *BatchOptions) *BatchReport {
+	return s.CreateBatchWithContext(context.Background(), events, options)
+}
+
+// CreateBatchWithContext is the context-aware version of CreateBatch. When ctx is done,
+// the events not sent yet fail with the context error
+func (s *EventService) CreateBatchWithContext(ctx context.Context, events []*Event, options *BatchOptions) *BatchReport {
+	workers := DefaultParallelWorkers
+	if options != nil && options.Workers > 0 {
+		workers = options.Workers
+	}
+
+	report := &BatchReport{Results: make([]BatchResult, len(events))}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			// Every result is written by a single worker, so no locking is needed
+			for i := range indexes {
+				result := &report.Results[i]
+				result.Index = i
+				if err := ctx.Err(); err != nil {
+					result.Err = err
+					continue
+				}
+				result.Event, result.Err = s.CreateWithContext(ctx, events[i])
+			}
+		}()
+	}
+
+	for i := range events {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return report
+}
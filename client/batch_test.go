@@ -0,0 +1,145 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestEventCreateBatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// Every third event is rejected
+	recorder := handleTrackedEvents(t, func(n, attempt int) int {
+		if n%3 == 0 {
+			return http.StatusBadRequest
+		}
+		return http.StatusOK
+	}, nil, nil)
+
+	events := make([]*Event, 10)
+	for n := range events {
+		events[n] = trackedEvent(n)
+	}
+	report := testClient.Events.CreateBatch(events, &BatchOptions{Workers: 2})
+
+	if len(report.Results) != 10 {
+		t.Fatalf("Expected 10 results, got %v", len(report.Results))
+	}
+	for i, result := range report.Results {
+		if result.Index != i {
+			t.Errorf("Expected index %v, got %v", i, result.Index)
+		}
+		if failed := i%3 == 0; failed != (result.Err != nil) || failed == (result.Event != nil) {
+			t.Errorf("Unexpected result for event %v: %+v", i, result)
+		}
+	}
+	if report.Succeeded() != 6 {
+		t.Errorf("Expected 6 events created, got %v", report.Succeeded())
+	}
+	if diff := pretty.Compare(report.FailedEvents(events), []*Event{events[0], events[3], events[6], events[9]}); diff != "" {
+		t.Errorf("FailedEvents: invalid events: (-got +expected)\n%s", diff)
+	}
+	if diff := pretty.Compare(recorder.sorted(), []int{1, 2, 4, 5, 7, 8}); diff != "" {
+		t.Errorf("CreateBatch: invalid events: (-got +expected)\n%s", diff)
+	}
+}
+
+func TestEventCreateBatchConcurrency(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// The requests are held until all the workers are busy
+	block := make(chan struct{})
+	started := make(chan int, 8)
+	handleTrackedEvents(t, statusOK, block, started)
+
+	events := make([]*Event, 8)
+	for n := range events {
+		events[n] = trackedEvent(n)
+	}
+	done := make(chan *BatchReport)
+	go func() {
+		done <- testClient.Events.CreateBatch(events, &BatchOptions{Workers: 4})
+	}()
+
+	timeout := time.After(time.Second)
+	for i := 0; i < 4; i++ {
+		select {
+		case <-started:
+		case <-timeout:
+			t.Fatalf("Expected 4 concurrent requests, got %v", i)
+		}
+	}
+	select {
+	case n := <-started:
+		t.Errorf("Expected at most 4 concurrent requests, event %v started too", n)
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(block)
+
+	if report := <-done; report.Succeeded() != 8 {
+		t.Errorf("Expected 8 events created, got %v", report.Failed())
+	}
+}
+
+func TestEventCreateBatchNil(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleTrackedEvents(t, statusOK, nil, nil)
+
+	report := testClient.Events.CreateBatch([]*Event{trackedEvent(0), nil, trackedEvent(2)}, nil)
+	failed := report.Failed()
+	if len(failed) != 1 || failed[0].Index != 1 || failed[0].Err != errNilEvent {
+		t.Errorf("Expected the nil event to fail, got %+v", failed)
+	}
+	if report.Succeeded() != 2 {
+		t.Errorf("Expected 2 events created, got %v", report.Succeeded())
+	}
+}
+
+func TestEventCreateBatchCancelled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleTrackedEvents(t, statusOK, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report := testClient.Events.CreateBatchWithContext(ctx, []*Event{trackedEvent(0), trackedEvent(1)}, nil)
+	for _, result := range report.Failed() {
+		if result.Err != context.Canceled {
+			t.Errorf("Expected context.Canceled, got %v", result.Err)
+		}
+	}
+	if len(report.Failed()) != 2 {
+		t.Errorf("Expected 2 failures, got %v", len(report.Failed()))
+	}
+}
@@ -0,0 +1,202 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	// ErrCheckpointChanged is returned by a resumed Iterator when the number of elements changed since the checkpoint
+	ErrCheckpointChanged = errors.New("the listing changed since the checkpoint")
+	// ErrCheckpointMismatch is returned when the checkpoint was saved for different list params
+	ErrCheckpointMismatch = errors.New("the checkpoint does not match the list params")
+)
+
+// Checkpoint records the progress of a listing, in order to resume it later
+type Checkpoint struct {
+	// Page is the last page which has been completely consumed
+	Page          int         `json:"page"`
+	PageSize      int         `json:"pageSize"`
+	QueryParams   QueryParams `json:"queryParams"`
+	TotalElements int         `json:"totalElements"`
+	UpdatedAt     time.Time   `json:"updatedAt"`
+}
+
+// CheckpointStore persists the Checkpoint of a listing
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, or nil if there is none
+	Load() (*Checkpoint, error)
+	Save(checkpoint *Checkpoint) error
+	Clear() error
+}
+
+// FileCheckpointStore is a CheckpointStore which saves the checkpoint as a JSON file
+type FileCheckpointStore struct {
+	Path string
+}
+
+// Load implements the CheckpointStore interface
+func (s *FileCheckpointStore) Load() (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := new(Checkpoint)
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// Save implements the CheckpointStore interface. The file is replaced atomically
+func (s *FileCheckpointStore) Save(checkpoint *Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+// Clear implements the CheckpointStore interface
+func (s *FileCheckpointStore) Clear() error {
+	if err := os.Remove(s.Path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// checkpointer saves the progress of an Iterator
+type checkpointer struct {
+	store       CheckpointStore
+	saved       *Checkpoint
+	pageSize    int
+	queryParams QueryParams
+	onChange    func(saved Checkpoint, current PageInfo) bool
+	verified    bool
+}
+
+// Resume makes the Iterator start after the last page recorded in store, if any, and save a checkpoint
+// every time a page has been completely consumed. The checkpoint is cleared when the listing is completed.
+// If the number of elements changed since the checkpoint, onChange decides whether to continue anyway:
+// otherwise, or if onChange is nil, the Iterator stops with ErrCheckpointChanged.
+// Resume must be called before Next, and returns ErrCheckpointMismatch if the checkpoint was saved for different params
+func (it *Iterator[T]) Resume(store CheckpointStore, onChange func(saved Checkpoint, current PageInfo) bool) error {
+	c := &checkpointer{
+		store:       store,
+		pageSize:    it.params.PageSize,
+		queryParams: QueryParams{},
+		onChange:    onChange,
+		verified:    true,
+	}
+	if c.pageSize == 0 {
+		c.pageSize = DefaultPageSize
+	}
+	for k, v := range it.params.QueryParams {
+		c.queryParams[k] = v
+	}
+
+	saved, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if saved != nil {
+		if !c.matches(saved) {
+			return ErrCheckpointMismatch
+		}
+		c.saved, c.verified = saved, false
+		it.params.Page = saved.Page + 1
+	}
+
+	it.checkpointer = c
+	return nil
+}
+
+func (c *checkpointer) matches(saved *Checkpoint) bool {
+	if saved.PageSize != c.pageSize || len(saved.QueryParams) != len(c.queryParams) {
+		return false
+	}
+	for k, v := range c.queryParams {
+		if savedValue, ok := saved.QueryParams[k]; !ok || savedValue != v {
+			return false
+		}
+	}
+	return true
+}
+
+// verify checks the first page fetched after resuming against the saved checkpoint
+func (c *checkpointer) verify(pageInfo PageInfo) error {
+	if c.verified {
+		return nil
+	}
+	c.verified = true
+	if pageInfo.TotalElements == c.saved.TotalElements {
+		return nil
+	}
+	if c.onChange != nil && c.onChange(*c.saved, pageInfo) {
+		return nil
+	}
+	return fmt.Errorf("%w: %d elements, were %d", ErrCheckpointChanged, pageInfo.TotalElements, c.saved.TotalElements)
+}
+
+// completed saves the checkpoint after a page has been consumed, or clears it at the end of the listing
+func (c *checkpointer) completed(pageInfo PageInfo, page int, last bool) error {
+	if last {
+		return c.store.Clear()
+	}
+	return c.store.Save(&Checkpoint{
+		Page:          page,
+		PageSize:      c.pageSize,
+		QueryParams:   c.queryParams,
+		TotalElements: pageInfo.TotalElements,
+		UpdatedAt:     time.Now(),
+	})
+}
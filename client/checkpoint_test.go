@@ -0,0 +1,158 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestIteratorResume(t *testing.T) {
+	store := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "customers.json")}
+	params := &ListParams{PageSize: 2, QueryParams: QueryParams{"query": "q"}}
+
+	setup()
+	handlePages(t, "/customers", 4, 2)
+	it := testClient.Customers.Iterate(context.Background(), params)
+	if err := it.Resume(store, nil); err != nil {
+		t.Fatalf("Unexpected error. Resume: %v", err)
+	}
+	for it.Next() {
+	}
+	teardown()
+	if !IsServerError(it.Err()) {
+		t.Fatalf("Expected server error, got %v", it.Err())
+	}
+
+	checkpoint, err := store.Load()
+	if err != nil {
+		t.Fatalf("Unexpected error. Load: %v", err)
+	}
+	checkpoint.UpdatedAt = checkpoint.UpdatedAt.UTC()
+	expected := &Checkpoint{Page: 1, PageSize: 2, QueryParams: QueryParams{"query": "q"}, TotalElements: 8, UpdatedAt: checkpoint.UpdatedAt}
+	if diff := pretty.Compare(checkpoint, expected); diff != "" {
+		t.Errorf("Checkpoint: invalid value: (-got +expected)\n%s", diff)
+	}
+
+	setup()
+	defer teardown()
+	requested := handlePages(t, "/customers", 4, -1)
+	it = testClient.Customers.Iterate(context.Background(), params)
+	if err := it.Resume(store, nil); err != nil {
+		t.Fatalf("Unexpected error. Resume: %v", err)
+	}
+	var IDs []string
+	for it.Next() {
+		IDs = append(IDs, it.Value().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Unexpected error. Iterator: %v", err)
+	}
+	if diff := pretty.Compare(IDs, []string{"2-0", "2-1", "3-0", "3-1"}); diff != "" {
+		t.Errorf("Iterator: invalid elements: (-got +expected)\n%s", diff)
+	}
+	if diff := pretty.Compare(*requested, []int{2, 3}); diff != "" {
+		t.Errorf("Iterator: invalid pages: (-got +expected)\n%s", diff)
+	}
+	if _, err := os.Stat(store.Path); !os.IsNotExist(err) {
+		t.Errorf("Expected the checkpoint to be cleared, got %v", err)
+	}
+}
+
+func TestIteratorResumeChanged(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handlePages(t, "/customers", 4, -1)
+	store := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "customers.json")}
+	saved := &Checkpoint{Page: 1, PageSize: 2, QueryParams: QueryParams{}, TotalElements: 6}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("Unexpected error. Save: %v", err)
+	}
+
+	it := testClient.Customers.Iterate(context.Background(), &ListParams{PageSize: 2})
+	if err := it.Resume(store, nil); err != nil {
+		t.Fatalf("Unexpected error. Resume: %v", err)
+	}
+	if it.Next() || !errors.Is(it.Err(), ErrCheckpointChanged) {
+		t.Fatalf("Expected ErrCheckpointChanged, got %v", it.Err())
+	}
+
+	var current PageInfo
+	it = testClient.Customers.Iterate(context.Background(), &ListParams{PageSize: 2})
+	err := it.Resume(store, func(saved Checkpoint, pageInfo PageInfo) bool {
+		current = pageInfo
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error. Resume: %v", err)
+	}
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Unexpected error. Iterator: %v", err)
+	}
+	if count != 4 || current.TotalElements != 8 {
+		t.Errorf("Expected 4 elements out of 8, got %v out of %v", count, current.TotalElements)
+	}
+}
+
+func TestIteratorResumeMismatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	store := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "customers.json")}
+	if err := store.Save(&Checkpoint{Page: 1, PageSize: 2, QueryParams: QueryParams{"query": "q"}}); err != nil {
+		t.Fatalf("Unexpected error. Save: %v", err)
+	}
+
+	cases := []*ListParams{
+		{PageSize: 2},
+		{PageSize: 2, QueryParams: QueryParams{"query": "other"}},
+		{QueryParams: QueryParams{"query": "q"}},
+	}
+	for _, params := range cases {
+		it := testClient.Customers.Iterate(context.Background(), params)
+		if err := it.Resume(store, nil); err != ErrCheckpointMismatch {
+			t.Errorf("Expected ErrCheckpointMismatch for %+v, got %v", params, err)
+		}
+	}
+}
+
+func TestFileCheckpointStoreEmpty(t *testing.T) {
+	store := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "missing.json")}
+	checkpoint, err := store.Load()
+	if err != nil || checkpoint != nil {
+		t.Errorf("Expected no checkpoint, got %v, %v", checkpoint, err)
+	}
+	if err := store.Clear(); err != nil {
+		t.Errorf("Unexpected error. Clear: %v", err)
+	}
+}
@@ -24,14 +24,13 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
+	"log/slog"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
 	"time"
 )
@@ -52,7 +51,26 @@ type Config struct {
 	DefaultNodeID string
 	WorkspaceID   string
 	Timeout       time.Duration
-	Debug         bool
+	// Debug logs every request and response, bodies included, to stdout when LogHandler is not set
+	Debug bool
+	// RetryPolicy enables automatic retries of transient failures. Nil disables retries
+	RetryPolicy *RetryPolicy
+	// RateLimit enables the client-side rate limiter. Nil disables it
+	RateLimit *RateLimit
+	// HTTPClient is used to perform the requests instead of a new http.Client. Timeout is ignored when set
+	HTTPClient *http.Client
+	// Transport overrides the RoundTripper of HTTPClient, e.g. for proxies or custom TLS settings
+	Transport http.RoundTripper
+	// LogHandler receives a structured record for every API call. Nil disables logging, unless Debug is set
+	LogHandler slog.Handler
+	// LogBodies adds the request headers and bodies to the log records, with sensitive fields redacted
+	LogBodies bool
+	// RedactFields are additional JSON fields to be redacted from the logged bodies
+	RedactFields []string
+	// Metrics receives the usage metrics of the client. Nil disables metrics
+	Metrics Metrics
+	// IdempotencyStore suppresses the creation of duplicate events. Nil disables it
+	IdempotencyStore IdempotencyStore
 }
 
 // QueryParams is simply a map of query paramss
@@ -67,6 +85,15 @@ type Client struct {
 	// HTTP client
 	client *http.Client
 
+	// Rate limiter shared by all services, may be nil
+	limiter *rateLimiter
+
+	// Middleware chain, see Use
+	middlewares []Middleware
+
+	// Logger of the API calls, may be nil
+	logger *callLogger
+
 	// User agent
 	UserAgent string
 
@@ -107,10 +134,20 @@ func New(config *Config) (*Client, error) {
 	if config.Timeout < 1 {
 		config.Timeout = DefaultTimeout
 	}
-	httpClient := &http.Client{
-		Timeout: config.Timeout * time.Millisecond,
+	if config.RetryPolicy != nil {
+		config.RetryPolicy.setDefaults()
 	}
+	httpClient := newHTTPClient(config, userAgent)
 	c := &Client{client: httpClient, BaseURL: baseURL, UserAgent: userAgent, Config: config}
+	c.logger = newCallLogger(config)
+
+	if config.RateLimit != nil {
+		limiter, err := newRateLimiter(config.RateLimit)
+		if err != nil {
+			return nil, err
+		}
+		c.limiter = limiter
+	}
 
 	c.Customers = &CustomerService{c}
 	c.Events = &EventService{c}
@@ -124,6 +161,11 @@ func New(config *Config) (*Client, error) {
 
 // NewRequest creates an API request. A relative URL can be provided in urlStr
 func (c *Client) NewRequest(method, path string, body interface{}) (*http.Request, error) {
+	return c.NewRequestWithContext(context.Background(), method, path, body)
+}
+
+// NewRequestWithContext creates an API request bound to ctx. A relative URL can be provided in urlStr
+func (c *Client) NewRequestWithContext(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
 	rel, err := url.Parse(path)
 	if err != nil {
 		return nil, err
@@ -136,45 +178,63 @@ func (c *Client) NewRequest(method, path string, body interface{}) (*http.Reques
 		return nil, err
 	}
 
-	if c.Config.Debug {
-		fmt.Printf("Request:\n(%s) %s\n%s\n", method, requestedURL.String(), encBody)
-	}
-
-	req, err := http.NewRequest(method, requestedURL.String(), encBody)
+	req, err := http.NewRequestWithContext(ctx, method, requestedURL.String(), encBody)
 	if err != nil {
 		return nil, err
 	}
 
-	bearerToken := "Bearer " + c.Config.APIkey
-
+	// User-Agent is added by the transport
 	req.Header.Add("Accept", contentType)
-	req.Header.Add("Authorization", bearerToken)
+	req.Header.Add("Authorization", "Bearer "+c.Config.APIkey)
 	req.Header.Add("Content-Type", contentType)
-	req.Header.Add("User-Agent", userAgent)
 	return req, nil
 }
 
-// Do actually perform the request
+// Do actually perform the request, through the middleware chain
+// The request is cancelled when the context of req is done
 func (c *Client) Do(req *http.Request, into interface{}) (*http.Response, error) {
-	resp, err := c.client.Do(req)
+	call, err := c.newCall(req, into)
+	if err != nil {
+		return nil, err
+	}
+	return c.handler()(call)
+}
+
+// do is the final Handler of the middleware chain
+func (c *Client) do(call *Call) (*http.Response, error) {
+	req, into := call.Request, call.Into
+	setBody(req, call.Body)
+
+	start := time.Now()
+	resp, err := c.send(call, req)
 
 	defer closeResponse(resp)
 
-	if err != nil {
-		return nil, err
+	if c.Config.Metrics != nil {
+		c.Config.Metrics.ObserveCall(call.Service, call.Operation, statusClass(resp), time.Since(start))
 	}
 
-	if c.Config.Debug {
-		dump, err := httputil.DumpResponse(resp, true)
-		if err != nil {
-			log.Fatal(err)
+	if err != nil {
+		// Prefer the context error, as it is more meaningful than the transport one
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			err = ctxErr
 		}
+		if c.logger != nil {
+			c.logger.log(call, nil, nil, err, time.Since(start))
+		}
+		return nil, err
+	}
 
-		fmt.Printf("Response:\n%s\n", string(dump))
+	var respBody []byte
+	if c.logger != nil && c.logger.bodies {
+		respBody = bufferBody(resp)
 	}
 
 	// Handle API errors
 	err = handleErrors(resp)
+	if c.logger != nil {
+		c.logger.log(call, resp, respBody, err, time.Since(start))
+	}
 	if err != nil {
 		return resp, err
 	}
@@ -195,6 +255,74 @@ func (c *Client) Do(req *http.Request, into interface{}) (*http.Response, error)
 	return resp, nil
 }
 
+// send performs the request, retrying it according to the RetryPolicy
+func (c *Client) send(call *Call, req *http.Request) (*http.Response, error) {
+	policy := c.Config.RetryPolicy
+	if policy == nil || !policy.canRetry(req) {
+		return c.attempt(call, req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.attempt(call, req)
+		if attempt >= policy.MaxAttempts || req.Context().Err() != nil || !policy.shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		wait := policy.backoff(attempt, resp)
+		if policy.OnRetry != nil {
+			policy.OnRetry(RetryEvent{Attempt: attempt, Request: req, Response: resp, Err: err, Wait: wait})
+		}
+		if c.Config.Metrics != nil {
+			c.Config.Metrics.ObserveRetry(call.Service, call.Operation)
+		}
+		closeResponse(resp)
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		if req, err = rewindRequest(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt performs a single round trip, once allowed by the rate limiter
+func (c *Client) attempt(call *Call, req *http.Request) (*http.Response, error) {
+	if c.limiter == nil {
+		return c.client.Do(req)
+	}
+
+	waited, err := c.limiter.wait(req.Context())
+	if waited > 0 && c.Config.Metrics != nil {
+		c.Config.Metrics.ObserveRateLimitWait(call.Service, call.Operation, waited)
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	c.limiter.observe(resp)
+	return resp, err
+}
+
+// rewindRequest returns a copy of req with a fresh body, ready to be sent again
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	newReq := req.Clone(req.Context())
+	newReq.Body = body
+	return newReq, nil
+}
+
+// DoWithContext performs the request bound to ctx, overriding the context of req
+func (c *Client) DoWithContext(ctx context.Context, req *http.Request, into interface{}) (*http.Response, error) {
+	return c.Do(req.WithContext(ctx), into)
+}
+
 // addQuery sets the query string parameters
 func addQuery(basePath string, queryParams QueryParams) string {
 	// Specify URL query string parameters
@@ -23,6 +23,8 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -151,3 +153,62 @@ func TestClientTimeout(t *testing.T) {
 		t.Error("Expected timeout.")
 	}
 }
+
+func TestNewRequestWithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx := context.WithValue(context.Background(), struct{}{}, "value")
+	req, err := testClient.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error. NewRequestWithContext: %v", err)
+	}
+
+	if req.Context() != ctx {
+		t.Error("Expected the request to be bound to the given context")
+	}
+}
+
+func TestDoWithCancelledContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	called := false
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		fmt.Fprint(w, "{}")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, _ := testClient.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	_, err := testClient.Do(req, nil)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if called {
+		t.Error("Expected the request not to reach the server")
+	}
+}
+
+func TestDoWithContextDeadline(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		fmt.Fprint(w, "{}")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	req, _ := testClient.NewRequest(http.MethodGet, "/", nil)
+	_, err := testClient.DoWithContext(ctx, req, nil)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+
+	"github.com/contactlab/contacthub-sdk-go/enums"
+)
+
+// ClientInfo contains the info about the client which generated the event
+type ClientInfo struct {
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+}
+
+func (c *ClientInfo) validate() error {
+	if c != nil && c.IP != "" && net.ParseIP(c.IP) == nil {
+		return fmt.Errorf("invalid client ip %q", c.IP)
+	}
+	return nil
+}
+
+// WebPage contains the info about the page where the event was generated
+type WebPage struct {
+	URL     string `json:"url,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Title   string `json:"title,omitempty"`
+	Referer string `json:"referer,omitempty"`
+}
+
+// StoreInfo contains the info about a physical or online store
+type StoreInfo struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Address string `json:"address,omitempty"`
+	City    string `json:"city,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+// DeviceInfo contains the info about a mobile or IoT device
+type DeviceInfo struct {
+	ID           string                  `json:"id,omitempty"`
+	Name         string                  `json:"name,omitempty"`
+	Model        string                  `json:"model,omitempty"`
+	Manufacturer string                  `json:"manufacturer,omitempty"`
+	Type         *enums.MobileDeviceType `json:"type,omitempty"`
+	OS           string                  `json:"os,omitempty"`
+	OSVersion    string                  `json:"osVersion,omitempty"`
+}
+
+// AppInfo contains the info about a mobile application
+type AppInfo struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// WebContextInfo is the ContextInfo of WEB events
+type WebContextInfo struct {
+	Client *ClientInfo `json:"client,omitempty"`
+	Page   *WebPage    `json:"page,omitempty"`
+}
+
+func (c *WebContextInfo) validate() error {
+	return c.Client.validate()
+}
+
+// EcommerceContextInfo is the ContextInfo of ECOMMERCE events
+type EcommerceContextInfo struct {
+	Client *ClientInfo `json:"client,omitempty"`
+	Page   *WebPage    `json:"page,omitempty"`
+	Store  *StoreInfo  `json:"store,omitempty"`
+}
+
+func (c *EcommerceContextInfo) validate() error {
+	return c.Client.validate()
+}
+
+// RetailContextInfo is the ContextInfo of RETAIL events
+type RetailContextInfo struct {
+	Store    *StoreInfo `json:"store"`
+	Salesman string     `json:"salesman,omitempty"`
+}
+
+func (c *RetailContextInfo) validate() error {
+	if c.Store == nil || (c.Store.ID == "" && c.Store.Name == "") {
+		return errors.New("store id or name is required")
+	}
+	return nil
+}
+
+// MobileContextInfo is the ContextInfo of MOBILE events
+type MobileContextInfo struct {
+	Client *ClientInfo `json:"client,omitempty"`
+	Device *DeviceInfo `json:"device,omitempty"`
+	App    *AppInfo    `json:"app,omitempty"`
+}
+
+func (c *MobileContextInfo) validate() error {
+	return c.Client.validate()
+}
+
+// IOTContextInfo is the ContextInfo of IOT events
+type IOTContextInfo struct {
+	Device   *DeviceInfo `json:"device"`
+	Location string      `json:"location,omitempty"`
+}
+
+func (c *IOTContextInfo) validate() error {
+	if c.Device == nil || c.Device.ID == "" {
+		return errors.New("device id is required")
+	}
+	return nil
+}
+
+// SocialContextInfo is the ContextInfo of SOCIAL events
+type SocialContextInfo struct {
+	Network string `json:"network,omitempty"`
+	PostURL string `json:"postUrl,omitempty"`
+}
+
+// DigitalCampaignContextInfo is the ContextInfo of DIGITAL_CAMPAIGN events
+type DigitalCampaignContextInfo struct {
+	Client  *ClientInfo `json:"client,omitempty"`
+	Channel string      `json:"channel,omitempty"`
+}
+
+func (c *DigitalCampaignContextInfo) validate() error {
+	return c.Client.validate()
+}
+
+// ContactCenterContextInfo is the ContextInfo of CONTACT_CENTER events
+type ContactCenterContextInfo struct {
+	Operator string `json:"operator,omitempty"`
+	Channel  string `json:"channel,omitempty"`
+}
+
+// GenericContextInfo is the free-form ContextInfo of OTHER events
+type GenericContextInfo map[string]interface{}
+
+// contextInfoTypes maps every EventContext to the type of its ContextInfo
+var contextInfoTypes = map[enums.EventContext]reflect.Type{
+	enums.Web:             reflect.TypeOf(WebContextInfo{}),
+	enums.Ecommerce:       reflect.TypeOf(EcommerceContextInfo{}),
+	enums.Retail:          reflect.TypeOf(RetailContextInfo{}),
+	enums.Social:          reflect.TypeOf(SocialContextInfo{}),
+	enums.DigitalCampaign: reflect.TypeOf(DigitalCampaignContextInfo{}),
+	enums.ContactCenter:   reflect.TypeOf(ContactCenterContextInfo{}),
+	enums.IOT:             reflect.TypeOf(IOTContextInfo{}),
+	enums.Other:           reflect.TypeOf(GenericContextInfo{}),
+	enums.MobileCtx:       reflect.TypeOf(MobileContextInfo{}),
+}
+
+// SetContextInfo sets the ContextInfo of the event from the typed struct of its Context,
+// e.g. WebContextInfo for WEB, either as a value or as a pointer
+func (e *Event) SetContextInfo(info interface{}) error {
+	expected, ok := contextInfoTypes[e.Context]
+	if !ok {
+		return fmt.Errorf("unknown event context %d", e.Context)
+	}
+	value := reflect.ValueOf(info)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if !value.IsValid() || value.Type() != expected {
+		return fmt.Errorf("invalid context info %T for event context %s, expected %v", info, eventContextName(e.Context), expected)
+	}
+
+	ptr := reflect.New(expected)
+	ptr.Elem().Set(value)
+	if err := validateContextInfo(e.Context, ptr.Interface()); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(ptr.Interface())
+	if err != nil {
+		return err
+	}
+	contextInfo := map[string]interface{}{}
+	if err := json.Unmarshal(data, &contextInfo); err != nil {
+		return err
+	}
+	e.ContextInfo = &contextInfo
+	return nil
+}
+
+// validate checks the known fields of the ContextInfo against the typed struct of its Context,
+// e.g. the client IP or the required store and device IDs. Fields missing from the struct are left
+// to the API, which accepts more than the typed structs describe
+func (e *Event) validate() error {
+	if e.ContextInfo == nil {
+		return nil
+	}
+	expected, ok := contextInfoTypes[e.Context]
+	if !ok {
+		return fmt.Errorf("unknown event context %d", e.Context)
+	}
+
+	data, err := json.Marshal(e.ContextInfo)
+	if err != nil {
+		return err
+	}
+	info := reflect.New(expected).Interface()
+	if err := json.Unmarshal(data, info); err != nil {
+		return fmt.Errorf("invalid context info for event context %s: %w", eventContextName(e.Context), err)
+	}
+	return validateContextInfo(e.Context, info)
+}
+
+func validateContextInfo(eventContext enums.EventContext, info interface{}) error {
+	if v, ok := info.(interface{ validate() error }); ok {
+		if err := v.validate(); err != nil {
+			return fmt.Errorf("invalid %s context info: %w", eventContextName(eventContext), err)
+		}
+	}
+	return nil
+}
+
+// DecodeContextInfo returns the ContextInfo of the event as a pointer to the typed struct of its Context,
+// e.g. *WebContextInfo for WEB
+func (r *EventResponse) DecodeContextInfo() (interface{}, error) {
+	expected, ok := contextInfoTypes[r.Context]
+	if !ok {
+		return nil, fmt.Errorf("unknown event context %d", r.Context)
+	}
+	info := reflect.New(expected).Interface()
+	data, err := json.Marshal(r.ContextInfo)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// eventContextName returns the API name of eventContext, for error messages
+func eventContextName(eventContext enums.EventContext) string {
+	name, err := enumValue(eventContext)
+	if err != nil {
+		return fmt.Sprint(int(eventContext))
+	}
+	return name
+}
@@ -0,0 +1,143 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/contactlab/contacthub-sdk-go/enums"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestContextInfoTypes(t *testing.T) {
+	for eventContext := enums.Web; eventContext <= enums.MobileCtx; eventContext++ {
+		if _, ok := contextInfoTypes[eventContext]; !ok {
+			t.Errorf("Missing context info type for %v", eventContextName(eventContext))
+		}
+	}
+}
+
+func TestEventSetContextInfo(t *testing.T) {
+	deviceType := enums.Android
+	event := &Event{Type: enums.LoggedIn, Context: enums.MobileCtx}
+	err := event.SetContextInfo(MobileContextInfo{
+		Client: &ClientInfo{IP: "10.0.0.1"},
+		Device: &DeviceInfo{ID: "device-1", Type: &deviceType},
+		App:    &AppInfo{Name: "shop", Version: "1.2"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error. SetContextInfo: %v", err)
+	}
+
+	expected := &map[string]interface{}{
+		"client": map[string]interface{}{"ip": "10.0.0.1"},
+		"device": map[string]interface{}{"id": "device-1", "type": "ANDROID"},
+		"app":    map[string]interface{}{"name": "shop", "version": "1.2"},
+	}
+	if diff := pretty.Compare(event.ContextInfo, expected); diff != "" {
+		t.Errorf("SetContextInfo: invalid context info: (-got +expected)\n%s", diff)
+	}
+
+	invalid := []interface{}{
+		&WebContextInfo{},
+		MobileContextInfo{Client: &ClientInfo{IP: "not-an-ip"}},
+		nil,
+	}
+	for _, info := range invalid {
+		if err := event.SetContextInfo(info); err == nil {
+			t.Errorf("Expected error for %T", info)
+		}
+	}
+	if err := (&Event{Context: enums.Retail}).SetContextInfo(RetailContextInfo{}); err == nil {
+		t.Error("Expected error for a retail context info without store")
+	}
+}
+
+func TestEventCreateInvalidContextInfo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request for an invalid context info")
+	})
+
+	cases := []*Event{
+		{Context: enums.Web, ContextInfo: &map[string]interface{}{"client": map[string]interface{}{"ip": "10.0.0"}}},
+		{Context: enums.Web, ContextInfo: &map[string]interface{}{"client": "10.0.0.1"}},
+		{Context: enums.IOT, ContextInfo: &map[string]interface{}{"location": "home"}},
+	}
+	for _, event := range cases {
+		if _, err := testClient.Events.Create(event); err == nil {
+			t.Errorf("Expected error for %+v", *event.ContextInfo)
+		}
+	}
+}
+
+func TestEventCreateUnknownContextInfo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"my-event-id"}`)
+	})
+
+	// Fields missing from the typed structs are accepted by the API
+	event := &Event{Context: enums.Web, ContextInfo: &map[string]interface{}{
+		"client": map[string]interface{}{
+			"ip":           "10.0.0.1",
+			"localization": map[string]interface{}{"city": "Torino"},
+		},
+	}}
+	if _, err := testClient.Events.Create(event); err != nil {
+		t.Errorf("Unexpected error. Events.Create: %v", err)
+	}
+}
+
+func TestEventDecodeContextInfo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/events/my-event-id", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"id":"my-event-id","type":"viewedPage","context":"WEB","properties":{},"contextInfo":{"client":{"ip":"111.111.111.111","userAgent":"Somebrowser/1.1"},"page":{"url":"https://example.com/"}}}`)
+	})
+
+	event, err := testClient.Events.Get("my-event-id")
+	if err != nil {
+		t.Fatalf("Unexpected error. Events.Get: %v", err)
+	}
+	info, err := event.DecodeContextInfo()
+	if err != nil {
+		t.Fatalf("Unexpected error. DecodeContextInfo: %v", err)
+	}
+
+	expected := &WebContextInfo{
+		Client: &ClientInfo{IP: "111.111.111.111", UserAgent: "Somebrowser/1.1"},
+		Page:   &WebPage{URL: "https://example.com/"},
+	}
+	if diff := pretty.Compare(info, expected); diff != "" {
+		t.Errorf("DecodeContextInfo: invalid context info: (-got +expected)\n%s", diff)
+	}
+}
@@ -23,7 +23,11 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/guregu/null"
@@ -90,8 +94,13 @@ type customerListResponse struct {
 
 // Get returns an individual customer by the ContactHub Customer ID
 func (s *CustomerService) Get(ID string) (*CustomerResponse, error) {
+	return s.GetWithContext(context.Background(), ID)
+}
+
+// GetWithContext is the context-aware version of Get
+func (s *CustomerService) GetWithContext(ctx context.Context, ID string) (*CustomerResponse, error) {
 	path := fmt.Sprintf("%s/%s", customerBasePath, ID)
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -107,8 +116,13 @@ func (s *CustomerService) Get(ID string) (*CustomerResponse, error) {
 // Delete deletes a customer by the ContactHub Customer ID
 // Note: the API returns an empty body
 func (s *CustomerService) Delete(ID string) error {
+	return s.DeleteWithContext(context.Background(), ID)
+}
+
+// DeleteWithContext is the context-aware version of Delete
+func (s *CustomerService) DeleteWithContext(ctx context.Context, ID string) error {
 	path := fmt.Sprintf("%s/%s", customerBasePath, ID)
-	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
@@ -123,10 +137,15 @@ func (s *CustomerService) Delete(ID string) error {
 
 // Create creates a new Customer on ContactHub
 func (s *CustomerService) Create(customer *Customer) (*CustomerResponse, error) {
+	return s.CreateWithContext(context.Background(), customer)
+}
+
+// CreateWithContext is the context-aware version of Create
+func (s *CustomerService) CreateWithContext(ctx context.Context, customer *Customer) (*CustomerResponse, error) {
 	if len(customer.NodeID) == 0 {
 		customer.NodeID = s.client.Config.DefaultNodeID
 	}
-	req, err := s.client.NewRequest(http.MethodPost, customerBasePath, customer)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPost, customerBasePath, customer)
 	if err != nil {
 		return nil, err
 	}
@@ -142,9 +161,14 @@ func (s *CustomerService) Create(customer *Customer) (*CustomerResponse, error)
 
 // Update updates a Customer on ContactHub, via a patch operation
 func (s *CustomerService) Update(ID string, customer *Customer) (*CustomerResponse, error) {
+	return s.UpdateWithContext(context.Background(), ID, customer)
+}
+
+// UpdateWithContext is the context-aware version of Update
+func (s *CustomerService) UpdateWithContext(ctx context.Context, ID string, customer *Customer) (*CustomerResponse, error) {
 	path := fmt.Sprintf("%s/%s", customerBasePath, ID)
 	customerRequest := customer.toPatchRequest()
-	req, err := s.client.NewRequest(http.MethodPatch, path, customerRequest)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPatch, path, customerRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -158,11 +182,62 @@ func (s *CustomerService) Update(ID string, customer *Customer) (*CustomerRespon
 	return createdCustomer, nil
 }
 
+// Upsert creates a new Customer on ContactHub or, if a customer with the same externalId or unique
+// properties already exists, updates it via a patch operation.
+// The returned bool is true if the customer has been created, false if it has been updated
+func (s *CustomerService) Upsert(customer *Customer) (*CustomerResponse, bool, error) {
+	return s.UpsertWithContext(context.Background(), customer)
+}
+
+// UpsertWithContext is the context-aware version of Upsert
+func (s *CustomerService) UpsertWithContext(ctx context.Context, customer *Customer) (*CustomerResponse, bool, error) {
+	createdCustomer, err := s.CreateWithContext(ctx, customer)
+	if err == nil {
+		return createdCustomer, true, nil
+	}
+
+	ID, ok := conflictingCustomerID(err)
+	if !ok {
+		return nil, false, err
+	}
+
+	updatedCustomer, err := s.UpdateWithContext(ctx, ID, customer)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return updatedCustomer, false, nil
+}
+
+// conflictingCustomerID extracts the ID of the existing customer from a 409 ErrorResponse
+// The API returns it as {"data": {"customer": {"id": "..."}}}
+func conflictingCustomerID(err error) (string, bool) {
+	var errorResponse *ErrorResponse
+	if !errors.As(err, &errorResponse) || !errorResponse.Is(ErrConflict) {
+		return "", false
+	}
+
+	var data struct {
+		Customer struct {
+			ID string `json:"id"`
+		} `json:"customer"`
+	}
+	if json.Unmarshal(errorResponse.RawData, &data) != nil || data.Customer.ID == "" {
+		return "", false
+	}
+	return data.Customer.ID, true
+}
+
 // List requests all customers from the default Node
 // The Node ID can be overriden via the QueryParams
 func (s *CustomerService) List(params *ListParams) ([]CustomerResponse, PageInfo, error) {
+	return s.ListWithContext(context.Background(), params)
+}
+
+// ListWithContext is the context-aware version of List
+func (s *CustomerService) ListWithContext(ctx context.Context, params *ListParams) ([]CustomerResponse, PageInfo, error) {
 	params.preparePagination()
-	customers, pageInfo, err := s.list(params, customerBasePath)
+	customers, pageInfo, err := s.list(ctx, params, customerBasePath)
 	if err != nil {
 		return nil, PageInfo{}, err
 	}
@@ -170,7 +245,17 @@ func (s *CustomerService) List(params *ListParams) ([]CustomerResponse, PageInfo
 	return customers, *pageInfo, err
 }
 
-func (s *CustomerService) list(params *ListParams, basePath string) ([]CustomerResponse, *PageInfo, error) {
+// Iterate returns an Iterator over all the customers matching params, starting from params.Page
+func (s *CustomerService) Iterate(ctx context.Context, params *ListParams) *Iterator[CustomerResponse] {
+	return newIterator(ctx, params, s.ListWithContext)
+}
+
+// ListAll returns all the customers matching params as a range-over-func sequence
+func (s *CustomerService) ListAll(ctx context.Context, params *ListParams) iter.Seq2[CustomerResponse, error] {
+	return s.Iterate(ctx, params).All()
+}
+
+func (s *CustomerService) list(ctx context.Context, params *ListParams, basePath string) ([]CustomerResponse, *PageInfo, error) {
 	// build url
 	if _, ok := params.QueryParams["nodeId"]; !ok {
 		params.QueryParams["nodeId"] = s.client.Config.DefaultNodeID
@@ -178,7 +263,7 @@ func (s *CustomerService) list(params *ListParams, basePath string) ([]CustomerR
 	path := addQuery(basePath, params.QueryParams)
 
 	List := &customerListResponse{}
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -23,6 +23,8 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -340,3 +342,109 @@ func TestCustomerUpdate(t *testing.T) {
 		t.Errorf("Client.Create: invalid value for struct: (-got +expected)\n%s", diff)
 	}
 }
+
+func TestCustomerGetWithContextCancelled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/my-customer-id", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected the request not to reach the server")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := testClient.Customers.GetWithContext(ctx, "my-customer-id")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCustomerListWithContextDeadline(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		fmt.Fprint(w, `{"page":{},"elements":[]}`)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	_, _, err := testClient.Customers.ListWithContext(ctx, &ListParams{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestCustomerUpsertCreate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"my-new-customer-id","nodeId":"fakenodeid","externalId":"my-external-id"}`)
+	})
+
+	customer := Customer{ExternalID: nullable.StringFrom("my-external-id")}
+	customerResponse, created, err := testClient.Customers.Upsert(&customer)
+	if err != nil {
+		t.Fatalf("Unexpected error. Customers.Upsert: %v", err)
+	}
+
+	if !created || customerResponse.ID != "my-new-customer-id" {
+		t.Errorf("Expected a created customer, got %v (created: %v)", customerResponse.ID, created)
+	}
+}
+
+func TestCustomerUpsertConflict(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"message":"Customer already exists","logref":"my-logref","data":{"customer":{"id":"my-existing-customer-id"}}}`)
+	})
+
+	expectedRequestBody := `{"externalId":"my-external-id","enabled":true}`
+	mux.HandleFunc("/customers/my-existing-customer-id", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if trimmedBody := strings.TrimSpace(string(body)); trimmedBody != expectedRequestBody {
+			t.Errorf("Customers.Upsert: invalid body. \nGot: %v\nExpected: %v", trimmedBody, expectedRequestBody)
+		}
+		fmt.Fprint(w, `{"id":"my-existing-customer-id","nodeId":"fakenodeid","externalId":"my-external-id","enabled":true}`)
+	})
+
+	customer := Customer{
+		ExternalID: nullable.StringFrom("my-external-id"),
+		Enabled:    nullable.BoolFrom(true),
+	}
+	customerResponse, created, err := testClient.Customers.Upsert(&customer)
+	if err != nil {
+		t.Fatalf("Unexpected error. Customers.Upsert: %v", err)
+	}
+
+	if created || customerResponse.ID != "my-existing-customer-id" {
+		t.Errorf("Expected an updated customer, got %v (created: %v)", customerResponse.ID, created)
+	}
+}
+
+func TestCustomerUpsertConflictWithoutID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"message":"Customer already exists","logref":"my-logref","data":null}`)
+	})
+
+	_, _, err := testClient.Customers.Upsert(&Customer{})
+	if !IsConflict(err) {
+		t.Errorf("Expected the conflict error, got %v", err)
+	}
+}
@@ -0,0 +1,579 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSegmentSize is the default maximum size in bytes of a DiskQueue segment
+	DefaultSegmentSize int64 = 16 << 20
+	// DefaultSyncInterval is the default fsync interval of the SyncPeriodic policy
+	DefaultSyncInterval = time.Second
+
+	segmentExt   = ".seg"
+	cursorFile   = "cursor"
+	recordHeader = 8
+)
+
+var (
+	// ErrQueueClosed is returned when enqueuing events on a closed DiskQueue
+	ErrQueueClosed = errors.New("event queue closed")
+	// ErrCorruptRecord is reported to OnCorruption when a segment record cannot be read back
+	ErrCorruptRecord = errors.New("corrupt event queue record")
+)
+
+// SyncPolicy decides when the DiskQueue segments are flushed to stable storage
+type SyncPolicy int
+
+const (
+	// SyncAlways fsyncs after every enqueued event and every acknowledgement
+	SyncAlways SyncPolicy = iota
+	// SyncPeriodic fsyncs every SyncInterval, so the last events may be lost on a crash
+	SyncPeriodic
+	// SyncNever leaves the flushing to the operating system
+	SyncNever
+)
+
+// DiskQueueConfig configures a DiskQueue. Zero values are replaced with the defaults
+type DiskQueueConfig struct {
+	// Dir is the directory of the segments, created if missing. It is required
+	Dir string
+	// SegmentSize is the size after which a new segment is started
+	SegmentSize  int64
+	Sync         SyncPolicy
+	SyncInterval time.Duration
+	// RetryPolicy decides which failures are transient and the backoff between the replays.
+	// Transient failures are retried until they succeed, regardless of MaxAttempts. Nil means DefaultRetryPolicy
+	RetryPolicy *RetryPolicy
+	// OnFailure, if set, is called for every event rejected by the API, which is then removed from the queue
+	OnFailure func(event *Event, err error)
+	// OnCorruption, if set, is called with an ErrCorruptRecord when a segment turns out to be corrupted,
+	// before it is removed together with the events following the corrupt record
+	OnCorruption func(err error)
+}
+
+// diskCursor is the position of the first event not acknowledged yet
+type diskCursor struct {
+	Segment uint64 `json:"segment"`
+	Offset  int64  `json:"offset"`
+}
+
+// queuedEvent is the payload of a DiskQueue record
+type queuedEvent struct {
+	Event          *Event `json:"event"`
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+}
+
+// DiskQueue is a write-ahead queue of events, persisted to append-only segments on disk.
+// The events are sent in order in the background, and removed once acknowledged by the API,
+// so they survive network outages and process restarts. Delivery is at-least-once
+type DiskQueue struct {
+	events *EventService
+	config DiskQueueConfig
+
+	// mu guards all the following fields
+	mu       sync.Mutex
+	segments []uint64
+	// counts are the numbers of events not acknowledged yet by segment
+	counts     map[uint64]int
+	active     *os.File
+	activeSize int64
+	cursor     diskCursor
+	pending    int
+	idle       chan struct{}
+	closed     bool
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+// NewDiskQueue opens the DiskQueue in config.Dir, recovering the events left by a previous process,
+// and starts replaying them
+func (s *EventService) NewDiskQueue(config *DiskQueueConfig) (*DiskQueue, error) {
+	if config == nil || config.Dir == "" {
+		return nil, errors.New("Dir is a required field")
+	}
+	q := &DiskQueue{events: s, config: *config, wake: make(chan struct{}, 1), counts: map[uint64]int{}}
+	if q.config.SegmentSize <= 0 {
+		q.config.SegmentSize = DefaultSegmentSize
+	}
+	if q.config.SyncInterval <= 0 {
+		q.config.SyncInterval = DefaultSyncInterval
+	}
+	if q.config.RetryPolicy == nil {
+		q.config.RetryPolicy = DefaultRetryPolicy()
+	} else {
+		policy := *q.config.RetryPolicy
+		policy.setDefaults()
+		q.config.RetryPolicy = &policy
+	}
+
+	if err := q.open(); err != nil {
+		return nil, err
+	}
+
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	q.done.Add(1)
+	go q.replay()
+	if q.config.Sync == SyncPeriodic {
+		q.done.Add(1)
+		go q.syncPeriodically()
+	}
+	return q, nil
+}
+
+// Enqueue persists the event, to be sent in the background
+func (q *DiskQueue) Enqueue(event *Event) error {
+	payload, err := json.Marshal(queuedEvent{Event: event, IdempotencyKey: event.IdempotencyKey})
+	if err != nil {
+		return err
+	}
+	record := make([]byte, recordHeader+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	copy(record[recordHeader:], payload)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	if q.activeSize > 0 && q.activeSize+int64(len(record)) > q.config.SegmentSize {
+		if err := q.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := q.active.Write(record)
+	q.activeSize += int64(n)
+	if err != nil {
+		// Drop the partial record, so that the segment stays readable
+		q.activeSize -= int64(n)
+		q.active.Truncate(q.activeSize)
+		return err
+	}
+	if q.config.Sync == SyncAlways {
+		if err := q.active.Sync(); err != nil {
+			// Drop the record as well, as the caller is going to retry
+			q.activeSize -= int64(n)
+			q.active.Truncate(q.activeSize)
+			return err
+		}
+	}
+
+	if q.pending == 0 {
+		q.idle = make(chan struct{})
+	}
+	q.pending++
+	q.counts[q.segments[len(q.segments)-1]]++
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Len returns the number of events waiting to be acknowledged
+func (q *DiskQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pending
+}
+
+// Flush waits until all the events enqueued so far have been acknowledged, or ctx is done
+func (q *DiskQueue) Flush(ctx context.Context) error {
+	q.mu.Lock()
+	idle := q.idle
+	q.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops the replay and closes the segments. The pending events are sent by the next DiskQueue
+// opened on the same directory
+func (q *DiskQueue) Close() error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	q.mu.Unlock()
+
+	q.cancel()
+	q.done.Wait()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.active.Sync(); err != nil {
+		q.active.Close()
+		return err
+	}
+	return q.active.Close()
+}
+
+// open loads the segments and the cursor, discarding the acknowledged segments and any torn record
+func (q *DiskQueue) open() error {
+	if err := os.MkdirAll(q.config.Dir, 0755); err != nil {
+		return err
+	}
+	files, err := ioutil.ReadDir(q.config.Dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if id, err := strconv.ParseUint(strings.TrimSuffix(file.Name(), segmentExt), 10, 64); err == nil && strings.HasSuffix(file.Name(), segmentExt) {
+			q.segments = append(q.segments, id)
+		}
+	}
+	sort.Slice(q.segments, func(i, j int) bool { return q.segments[i] < q.segments[j] })
+
+	data, err := ioutil.ReadFile(filepath.Join(q.config.Dir, cursorFile))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &q.cursor); err != nil {
+			return fmt.Errorf("invalid queue cursor: %w", err)
+		}
+	case os.IsNotExist(err):
+		if len(q.segments) > 0 {
+			q.cursor.Segment = q.segments[0]
+		}
+	default:
+		return err
+	}
+
+	for len(q.segments) > 0 && q.segments[0] < q.cursor.Segment {
+		if err := os.Remove(q.segmentPath(q.segments[0])); err != nil {
+			return err
+		}
+		q.segments = q.segments[1:]
+	}
+	if len(q.segments) == 0 || q.segments[0] != q.cursor.Segment {
+		q.cursor = diskCursor{Segment: q.cursor.Segment + 1}
+		if len(q.segments) > 0 {
+			q.cursor.Segment = q.segments[0]
+		}
+	}
+
+	for i, id := range q.segments {
+		offset := int64(0)
+		if id == q.cursor.Segment {
+			offset = q.cursor.Offset
+		}
+		count, size, err := q.scan(id, offset)
+		if err != nil {
+			return err
+		}
+		q.pending += count
+		q.counts[id] = count
+		if i == len(q.segments)-1 {
+			// Truncate the torn record left by a crash, if any
+			if err := os.Truncate(q.segmentPath(id), size); err != nil {
+				return err
+			}
+			q.activeSize = size
+		}
+	}
+	q.idle = make(chan struct{})
+	if q.pending == 0 {
+		close(q.idle)
+	}
+
+	if len(q.segments) == 0 {
+		q.segments = []uint64{q.cursor.Segment}
+	}
+	q.active, err = os.OpenFile(q.segmentPath(q.segments[len(q.segments)-1]), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	return err
+}
+
+// scan counts the valid records of a segment from offset, returning the size of its valid part
+func (q *DiskQueue) scan(id uint64, offset int64) (int, int64, error) {
+	f, err := os.Open(q.segmentPath(id))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	count := 0
+	for {
+		_, next, err := readRecord(f, offset)
+		if err == io.EOF || errors.Is(err, ErrCorruptRecord) {
+			return count, offset, nil
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		count++
+		offset = next
+	}
+}
+
+// rotate starts a new active segment
+func (q *DiskQueue) rotate() error {
+	if err := q.active.Sync(); err != nil {
+		return err
+	}
+	if err := q.active.Close(); err != nil {
+		return err
+	}
+	id := q.segments[len(q.segments)-1] + 1
+	active, err := os.OpenFile(q.segmentPath(id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	q.active, q.activeSize = active, 0
+	q.segments = append(q.segments, id)
+	return nil
+}
+
+// replay sends the events in order, until the queue is closed
+func (q *DiskQueue) replay() {
+	defer q.done.Done()
+	policy := q.config.RetryPolicy
+	attempt := 0
+
+	for {
+		payload, next, ok, err := q.peek()
+		if err != nil || !ok {
+			select {
+			case <-q.wake:
+				continue
+			case <-q.ctx.Done():
+				return
+			}
+		}
+
+		queued := queuedEvent{Event: new(Event)}
+		if err := json.Unmarshal(payload, &queued); err != nil {
+			q.fail(queued.Event, err)
+			q.ack(next)
+			continue
+		}
+		event := queued.Event
+		event.IdempotencyKey = queued.IdempotencyKey
+
+		_, err = q.events.CreateWithContext(q.ctx, event)
+		if q.ctx.Err() != nil {
+			// Keep the event for the next DiskQueue
+			return
+		}
+		if err != nil {
+			if resp, retry := policy.transient(err); retry {
+				attempt++
+				if sleep(q.ctx, policy.backoff(attempt, resp)) != nil {
+					return
+				}
+				continue
+			}
+			q.fail(event, err)
+		}
+		attempt = 0
+		q.ack(next)
+	}
+}
+
+// peek reads the first event not acknowledged yet, moving to the next segment when the current one is consumed.
+// A corrupt segment is reported to OnCorruption and removed
+func (q *DiskQueue) peek() ([]byte, diskCursor, bool, error) {
+	var corrupted []error
+	q.mu.Lock()
+	defer func() {
+		q.mu.Unlock()
+		for _, err := range corrupted {
+			if q.config.OnCorruption != nil {
+				q.config.OnCorruption(err)
+			}
+		}
+	}()
+
+	for {
+		segment := q.cursor.Segment
+		f, err := os.Open(q.segmentPath(segment))
+		if err != nil {
+			return nil, q.cursor, false, err
+		}
+		payload, next, err := readRecord(f, q.cursor.Offset)
+		f.Close()
+		switch {
+		case err == nil:
+			return payload, diskCursor{Segment: segment, Offset: next}, true, nil
+		case err != io.EOF && !errors.Is(err, ErrCorruptRecord):
+			return nil, q.cursor, false, err
+		}
+
+		corrupt := err != io.EOF
+		if len(q.segments) == 1 {
+			if !corrupt {
+				// The active segment is consumed
+				return nil, q.cursor, false, nil
+			}
+			// The records are appended after the corrupt one, so a new active segment is needed
+			if err := q.rotate(); err != nil {
+				return nil, q.cursor, false, err
+			}
+		}
+		if corrupt {
+			lost := q.counts[segment]
+			corrupted = append(corrupted, fmt.Errorf("%w in segment %s at offset %d, the rest of the segment is discarded",
+				ErrCorruptRecord, filepath.Base(q.segmentPath(segment)), q.cursor.Offset))
+			q.pending -= lost
+			if lost > 0 && q.pending == 0 {
+				close(q.idle)
+			}
+		}
+		delete(q.counts, segment)
+
+		// Delete the consumed segment, which is not going to be written anymore
+		if err := os.Remove(q.segmentPath(q.segments[0])); err != nil {
+			return nil, q.cursor, false, err
+		}
+		q.segments = q.segments[1:]
+		q.cursor = diskCursor{Segment: q.segments[0]}
+		if err := q.saveCursor(); err != nil {
+			return nil, q.cursor, false, err
+		}
+	}
+}
+
+// ack marks the peeked event as acknowledged
+func (q *DiskQueue) ack(next diskCursor) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.cursor = next
+	q.saveCursor()
+	q.counts[next.Segment]--
+	q.pending--
+	if q.pending == 0 {
+		close(q.idle)
+	}
+}
+
+func (q *DiskQueue) fail(event *Event, err error) {
+	if q.config.OnFailure != nil {
+		q.config.OnFailure(event, err)
+	}
+}
+
+// saveCursor persists the cursor, replacing the file atomically
+func (q *DiskQueue) saveCursor() error {
+	data, err := json.Marshal(q.cursor)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(q.config.Dir, cursorFile)
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if q.config.Sync == SyncAlways {
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (q *DiskQueue) syncPeriodically() {
+	defer q.done.Done()
+	ticker := time.NewTicker(q.config.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			q.mu.Lock()
+			q.active.Sync()
+			q.mu.Unlock()
+		case <-q.ctx.Done():
+			return
+		}
+	}
+}
+
+func (q *DiskQueue) segmentPath(id uint64) string {
+	return filepath.Join(q.config.Dir, fmt.Sprintf("%020d%s", id, segmentExt))
+}
+
+// readRecord reads the record at offset, returning its payload and the offset of the next one.
+// It returns io.EOF at the end of the segment, and ErrCorruptRecord for incomplete and corrupted records
+func readRecord(f *os.File, offset int64) ([]byte, int64, error) {
+	header := make([]byte, recordHeader)
+	if n, err := f.ReadAt(header, offset); err != nil {
+		switch {
+		case err == io.EOF && n == 0:
+			return nil, offset, io.EOF
+		case err == io.EOF:
+			return nil, offset, ErrCorruptRecord
+		}
+		return nil, offset, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return nil, offset, err
+	}
+	// Check the length before allocating, as it may be corrupted as well
+	length := int64(binary.BigEndian.Uint32(header[0:4]))
+	if offset+recordHeader+length > info.Size() {
+		return nil, offset, ErrCorruptRecord
+	}
+	payload := make([]byte, length)
+	if _, err := f.ReadAt(payload, offset+recordHeader); err != nil {
+		return nil, offset, ErrCorruptRecord
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, offset, ErrCorruptRecord
+	}
+	return payload, offset + recordHeader + int64(len(payload)), nil
+}
@@ -0,0 +1,292 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// segmentFiles lists the segments in dir
+func segmentFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if err != nil {
+		t.Fatalf("Unexpected error. Glob: %v", err)
+	}
+	return files
+}
+
+func flushQueue(t *testing.T, q *DiskQueue) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := q.Flush(ctx); err != nil {
+		t.Fatalf("Unexpected error. Flush: %v, %v events pending", err, q.Len())
+	}
+}
+
+func TestDiskQueueReplay(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// The API is unreachable until online is set
+	var online int32
+	recorder := handleTrackedEvents(t, func(n, attempt int) int {
+		if atomic.LoadInt32(&online) == 0 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}, nil, nil)
+
+	dir := t.TempDir()
+	q, err := testClient.Events.NewDiskQueue(&DiskQueueConfig{Dir: dir, SegmentSize: 200, RetryPolicy: testRetryPolicy()})
+	if err != nil {
+		t.Fatalf("Unexpected error. NewDiskQueue: %v", err)
+	}
+	defer q.Close()
+
+	for n := 0; n < 5; n++ {
+		if err := q.Enqueue(trackedEvent(n)); err != nil {
+			t.Fatalf("Unexpected error. Enqueue: %v", err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if q.Len() != 5 || len(recorder.sorted()) != 0 {
+		t.Fatalf("Expected 5 pending events while offline, got %v", q.Len())
+	}
+	if files := segmentFiles(t, dir); len(files) < 2 {
+		t.Errorf("Expected the segments to be rotated, got %v", files)
+	}
+
+	atomic.StoreInt32(&online, 1)
+	flushQueue(t, q)
+
+	if diff := pretty.Compare(recorder.received, []int{0, 1, 2, 3, 4}); diff != "" {
+		t.Errorf("Replay: invalid events: (-got +expected)\n%s", diff)
+	}
+	if files := segmentFiles(t, dir); len(files) != 1 {
+		t.Errorf("Expected the acknowledged segments to be deleted, got %v", files)
+	}
+}
+
+func TestDiskQueueRestart(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var online int32
+	recorder := handleTrackedEvents(t, func(n, attempt int) int {
+		if atomic.LoadInt32(&online) == 0 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}, nil, nil)
+
+	dir := t.TempDir()
+	config := &DiskQueueConfig{Dir: dir, SegmentSize: 200, Sync: SyncPeriodic, SyncInterval: time.Millisecond, RetryPolicy: testRetryPolicy()}
+	q, err := testClient.Events.NewDiskQueue(config)
+	if err != nil {
+		t.Fatalf("Unexpected error. NewDiskQueue: %v", err)
+	}
+	for n := 0; n < 3; n++ {
+		q.Enqueue(trackedEvent(n))
+	}
+	if err := q.Close(); err != nil {
+		t.Fatalf("Unexpected error. Close: %v", err)
+	}
+	if err := q.Enqueue(trackedEvent(3)); err != ErrQueueClosed {
+		t.Errorf("Expected ErrQueueClosed, got %v", err)
+	}
+
+	// Simulate a crash in the middle of a write
+	files := segmentFiles(t, dir)
+	f, err := os.OpenFile(files[len(files)-1], os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("Unexpected error. OpenFile: %v", err)
+	}
+	f.Write([]byte{0, 0, 1, 0, 42})
+	f.Close()
+
+	atomic.StoreInt32(&online, 1)
+	q, err = testClient.Events.NewDiskQueue(config)
+	if err != nil {
+		t.Fatalf("Unexpected error. NewDiskQueue: %v", err)
+	}
+	defer q.Close()
+	q.Enqueue(trackedEvent(3))
+	flushQueue(t, q)
+
+	if diff := pretty.Compare(recorder.received, []int{0, 1, 2, 3}); diff != "" {
+		t.Errorf("Restart: invalid events: (-got +expected)\n%s", diff)
+	}
+}
+
+func TestDiskQueueFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	recorder := handleTrackedEvents(t, func(n, attempt int) int {
+		if n == 1 {
+			return http.StatusBadRequest
+		}
+		return http.StatusOK
+	}, nil, nil)
+
+	var failed []int
+	q, err := testClient.Events.NewDiskQueue(&DiskQueueConfig{
+		Dir:         t.TempDir(),
+		RetryPolicy: testRetryPolicy(),
+		OnFailure: func(event *Event, err error) {
+			if !IsValidation(err) {
+				t.Errorf("Expected validation error, got %v", err)
+			}
+			failed = append(failed, int(event.Properties["n"].(float64)))
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error. NewDiskQueue: %v", err)
+	}
+	defer q.Close()
+
+	for n := 0; n < 3; n++ {
+		q.Enqueue(trackedEvent(n))
+	}
+	flushQueue(t, q)
+
+	if diff := pretty.Compare(failed, []int{1}); diff != "" {
+		t.Errorf("OnFailure: invalid events: (-got +expected)\n%s", diff)
+	}
+	if diff := pretty.Compare(recorder.received, []int{0, 2}); diff != "" {
+		t.Errorf("Replay: invalid events: (-got +expected)\n%s", diff)
+	}
+}
+
+func TestDiskQueueGatewayError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// A gateway replies with an HTML page while the API is down
+	var attempts int32
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("<html><body>503 Service Unavailable</body></html>"))
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+
+	var failed []error
+	q, err := testClient.Events.NewDiskQueue(&DiskQueueConfig{
+		Dir:         t.TempDir(),
+		RetryPolicy: testRetryPolicy(),
+		OnFailure: func(event *Event, err error) {
+			failed = append(failed, err)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error. NewDiskQueue: %v", err)
+	}
+	defer q.Close()
+
+	q.Enqueue(trackedEvent(0))
+	flushQueue(t, q)
+
+	if len(failed) != 0 {
+		t.Errorf("Expected the event to be retried, got failures %v", failed)
+	}
+	if n := atomic.LoadInt32(&attempts); n != 3 {
+		t.Errorf("Expected 3 attempts, got %v", n)
+	}
+}
+
+func TestDiskQueueCorruption(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var online int32
+	recorder := handleTrackedEvents(t, func(n, attempt int) int {
+		if atomic.LoadInt32(&online) == 0 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}, nil, nil)
+
+	// One event per segment
+	var corrupted []error
+	dir := t.TempDir()
+	config := &DiskQueueConfig{
+		Dir:          dir,
+		SegmentSize:  1,
+		RetryPolicy:  testRetryPolicy(),
+		OnCorruption: func(err error) { corrupted = append(corrupted, err) },
+	}
+	q, err := testClient.Events.NewDiskQueue(config)
+	if err != nil {
+		t.Fatalf("Unexpected error. NewDiskQueue: %v", err)
+	}
+	for n := 0; n < 4; n++ {
+		q.Enqueue(trackedEvent(n))
+	}
+	q.Close()
+
+	// Flip a byte of the payload of the second event
+	files := segmentFiles(t, dir)
+	data, err := os.ReadFile(files[1])
+	if err != nil {
+		t.Fatalf("Unexpected error. ReadFile: %v", err)
+	}
+	data[recordHeader+1] ^= 0xff
+	os.WriteFile(files[1], data, 0644)
+
+	atomic.StoreInt32(&online, 1)
+	q, err = testClient.Events.NewDiskQueue(config)
+	if err != nil {
+		t.Fatalf("Unexpected error. NewDiskQueue: %v", err)
+	}
+	defer q.Close()
+	flushQueue(t, q)
+
+	if diff := pretty.Compare(recorder.received, []int{0, 2, 3}); diff != "" {
+		t.Errorf("Replay: invalid events: (-got +expected)\n%s", diff)
+	}
+	if len(corrupted) != 1 || !errors.Is(corrupted[0], ErrCorruptRecord) {
+		t.Errorf("Expected the corrupt segment to be reported, got %v", corrupted)
+	}
+	if files := segmentFiles(t, dir); len(files) != 1 {
+		t.Errorf("Expected the consumed segments to be deleted, got %v", files)
+	}
+}
+
+func TestDiskQueueRequiresDir(t *testing.T) {
+	if _, err := (&EventService{}).NewDiskQueue(&DiskQueueConfig{}); err == nil {
+		t.Error("Expected error without Dir")
+	}
+}
@@ -23,6 +23,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -62,8 +63,13 @@ type EducationService struct {
 
 // Get returns an individual education of a customer
 func (s *EducationService) Get(customerID, ID string) (*EducationResponse, error) {
+	return s.GetWithContext(context.Background(), customerID, ID)
+}
+
+// GetWithContext is the context-aware version of Get
+func (s *EducationService) GetWithContext(ctx context.Context, customerID, ID string) (*EducationResponse, error) {
 	path := fmt.Sprintf(educationBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -78,8 +84,13 @@ func (s *EducationService) Get(customerID, ID string) (*EducationResponse, error
 
 // Create creates a new Education for the Customer, returns the response
 func (s *EducationService) Create(customerID string, education *Education) (*EducationResponse, error) {
+	return s.CreateWithContext(context.Background(), customerID, education)
+}
+
+// CreateWithContext is the context-aware version of Create
+func (s *EducationService) CreateWithContext(ctx context.Context, customerID string, education *Education) (*EducationResponse, error) {
 	path := fmt.Sprintf(educationBasePath, customerID)
-	req, err := s.client.NewRequest(http.MethodPost, path, education)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPost, path, education)
 	if err != nil {
 		return nil, err
 	}
@@ -95,8 +106,13 @@ func (s *EducationService) Create(customerID string, education *Education) (*Edu
 
 // Update updates a Education via a put operation
 func (s *EducationService) Update(customerID, ID string, education *Education) (*EducationResponse, error) {
+	return s.UpdateWithContext(context.Background(), customerID, ID, education)
+}
+
+// UpdateWithContext is the context-aware version of Update
+func (s *EducationService) UpdateWithContext(ctx context.Context, customerID, ID string, education *Education) (*EducationResponse, error) {
 	path := fmt.Sprintf(educationBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodPut, path, education)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPut, path, education)
 	if err != nil {
 		return nil, err
 	}
@@ -112,8 +128,13 @@ func (s *EducationService) Update(customerID, ID string, education *Education) (
 
 // Delete deletes a Education
 func (s *EducationService) Delete(customerID, ID string) error {
+	return s.DeleteWithContext(context.Background(), customerID, ID)
+}
+
+// DeleteWithContext is the context-aware version of Delete
+func (s *EducationService) DeleteWithContext(ctx context.Context, customerID, ID string) error {
 	path := fmt.Sprintf(educationBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
@@ -24,6 +24,7 @@ package client
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -33,13 +34,47 @@ import (
 	"strings"
 )
 
+// Sentinel errors matched by ErrorResponse via errors.Is, according to the response status code
+// Note that ErrRateLimited is matched by 429 responses as well
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrServerError  = errors.New("server error")
+)
+
 // ErrorResponse represents an error response from the ContactHub API, which may contain multiple errors
 type ErrorResponse struct {
 	*http.Response
 	Message string      `json:"message"`
 	Logref  string      `json:"logref"`
 	Data    zero.String `json:"data"`
-	Errors  []APIError  `json:"errors"`
+	// RawData contains the data field when it is not a string, e.g. the existing customer on conflicts
+	RawData json.RawMessage `json:"-"`
+	Errors  []APIError      `json:"errors"`
+}
+
+// UnmarshalJSON implements the Unmarshaler interface, accepting any JSON value as data
+func (r *ErrorResponse) UnmarshalJSON(b []byte) error {
+	var body struct {
+		Message string          `json:"message"`
+		Logref  string          `json:"logref"`
+		Data    json.RawMessage `json:"data"`
+		Errors  []APIError      `json:"errors"`
+	}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return err
+	}
+
+	r.Message, r.Logref, r.Errors = body.Message, body.Logref, body.Errors
+	if len(body.Data) > 0 && string(body.Data) != "null" {
+		if err := json.Unmarshal(body.Data, &r.Data); err != nil {
+			r.RawData = body.Data
+		}
+	}
+	return nil
 }
 
 // APIError contains info about a ContactHub error
@@ -61,17 +96,95 @@ func (r *ErrorResponse) Error() string {
 		r.Response.StatusCode, r.Response.Request.Method, r.Response.Request.URL, strings.Join(messages, ", "))
 }
 
+// Is maps the status code of the response to the sentinel errors, to be used via errors.Is
+func (r *ErrorResponse) Is(target error) bool {
+	if r.Response == nil {
+		return false
+	}
+	switch code := r.Response.StatusCode; {
+	case code == http.StatusNotFound:
+		return target == ErrNotFound
+	case code == http.StatusConflict:
+		return target == ErrConflict
+	case code == http.StatusBadRequest || code == http.StatusUnprocessableEntity:
+		return target == ErrValidation
+	case code == http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case code == http.StatusForbidden:
+		return target == ErrForbidden
+	case code == http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	case code >= 500:
+		return target == ErrServerError
+	}
+	return false
+}
+
+// FieldErrors returns the error messages by field path, e.g. "/base/credential/username"
+func (r *ErrorResponse) FieldErrors() map[string][]string {
+	fieldErrors := map[string][]string{}
+	for _, apiError := range r.Errors {
+		fieldErrors[apiError.Path] = append(fieldErrors[apiError.Path], apiError.Message)
+	}
+	return fieldErrors
+}
+
+// Logref returns the API logref of err, if it is an ErrorResponse, or an empty string
+func Logref(err error) string {
+	var errorResponse *ErrorResponse
+	if errors.As(err, &errorResponse) {
+		return errorResponse.Logref
+	}
+	return ""
+}
+
+// IsNotFound checks whether err is a 404 ErrorResponse
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict checks whether err is a 409 ErrorResponse
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsValidation checks whether err is a 400 or 422 ErrorResponse
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// IsUnauthorized checks whether err is a 401 ErrorResponse
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden checks whether err is a 403 ErrorResponse
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsRateLimited checks whether err is a 429 ErrorResponse, or the client rate limiter failing fast
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsServerError checks whether err is a 5xx ErrorResponse
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrServerError)
+}
+
 func handleErrors(r *http.Response) error {
 	if c := r.StatusCode; c >= 200 && c <= 299 {
 		return nil
 	}
 
+	// The sentinels are keyed on the status code, so an ErrorResponse is returned even when the body is not JSON,
+	// e.g. the HTML or plain text pages of a proxy, keeping the raw body as Message
 	errorResponse := &ErrorResponse{Response: r}
 	data, err := ioutil.ReadAll(r.Body)
 	if err == nil && len(data) > 0 {
-		err := json.Unmarshal(data, errorResponse)
-		if err != nil {
-			return err
+		if err := json.Unmarshal(data, errorResponse); err != nil {
+			errorResponse.Message = strings.TrimSpace(string(data))
 		}
 	}
 
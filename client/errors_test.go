@@ -23,6 +23,8 @@
 package client
 
 import (
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -76,3 +78,95 @@ func TestErrorResponse(t *testing.T) {
 	}
 
 }
+
+func TestErrorResponseIs(t *testing.T) {
+	cases := []struct {
+		statusCode int
+		sentinel   error
+		check      func(error) bool
+	}{
+		{http.StatusNotFound, ErrNotFound, IsNotFound},
+		{http.StatusConflict, ErrConflict, IsConflict},
+		{http.StatusBadRequest, ErrValidation, IsValidation},
+		{http.StatusUnprocessableEntity, ErrValidation, IsValidation},
+		{http.StatusUnauthorized, ErrUnauthorized, IsUnauthorized},
+		{http.StatusForbidden, ErrForbidden, IsForbidden},
+		{http.StatusTooManyRequests, ErrRateLimited, IsRateLimited},
+		{http.StatusBadGateway, ErrServerError, IsServerError},
+	}
+
+	for _, c := range cases {
+		err := fmt.Errorf("wrapped: %w", &ErrorResponse{Response: &http.Response{StatusCode: c.statusCode}})
+		if !errors.Is(err, c.sentinel) || !c.check(err) {
+			t.Errorf("Expected %v to match %v", c.statusCode, c.sentinel)
+		}
+		if c.sentinel != ErrNotFound && IsNotFound(err) {
+			t.Errorf("Expected %v not to match ErrNotFound", c.statusCode)
+		}
+	}
+
+	if IsNotFound(errors.New("not found")) {
+		t.Error("Expected plain errors not to match")
+	}
+}
+
+func TestErrorResponseNotJSON(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/my-customer-id", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "<html><body>502 Bad Gateway</body></html>\n")
+	})
+
+	_, err := testClient.Customers.Get("my-customer-id")
+	if !IsServerError(err) || !errors.Is(err, ErrServerError) {
+		t.Fatalf("Expected server error, got %v", err)
+	}
+
+	var errorResponse *ErrorResponse
+	if !errors.As(err, &errorResponse) {
+		t.Fatal("Expected *ErrorResponse")
+	}
+	if errorResponse.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected status code 502, got %v", errorResponse.StatusCode)
+	}
+	if errorResponse.Message != "<html><body>502 Bad Gateway</body></html>" {
+		t.Errorf("Expected the raw body as message, got '%v'", errorResponse.Message)
+	}
+}
+
+func TestErrorResponseFields(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"message":"source customer is not valid","logref":"my-logref","errors":[{"message":"unique customer property is required","path":"/base/credential/username"},{"message":"invalid email","path":"/base/contacts/email"}]}`)
+	})
+
+	_, err := testClient.Customers.Create(&Customer{})
+	if !IsValidation(err) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	if logref := Logref(err); logref != "my-logref" {
+		t.Errorf("Expected logref 'my-logref', got '%v'", logref)
+	}
+
+	var errorResponse *ErrorResponse
+	if !errors.As(err, &errorResponse) {
+		t.Fatal("Expected *ErrorResponse")
+	}
+	expected := map[string][]string{
+		"/base/credential/username": {"unique customer property is required"},
+		"/base/contacts/email":      {"invalid email"},
+	}
+	if diff := pretty.Compare(errorResponse.FieldErrors(), expected); diff != "" {
+		t.Errorf("FieldErrors: invalid value: (-got +expected)\n%s", diff)
+	}
+
+	if logref := Logref(errors.New("other")); logref != "" {
+		t.Errorf("Expected no logref, got '%v'", logref)
+	}
+}
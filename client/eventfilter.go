@@ -0,0 +1,102 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/contactlab/contacthub-sdk-go/enums"
+)
+
+// EventFilter contains the filters of an events listing. Nil and zero fields are ignored
+type EventFilter struct {
+	Type    *enums.EventType
+	Context *enums.EventContext
+	Mode    *enums.EventMode
+	// From and To restrict the event date, both included
+	From time.Time
+	To   time.Time
+}
+
+// QueryParams encodes the filter into the params expected by the events List endpoint
+func (f *EventFilter) QueryParams() (QueryParams, error) {
+	if !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+		return nil, errors.New("the event filter ends before it starts")
+	}
+
+	params := QueryParams{}
+	enumParams := []struct {
+		key   string
+		value json.Marshaler
+		set   bool
+	}{
+		{"type", f.Type, f.Type != nil},
+		{"context", f.Context, f.Context != nil},
+		{"mode", f.Mode, f.Mode != nil},
+	}
+	for _, p := range enumParams {
+		if !p.set {
+			continue
+		}
+		value, err := enumValue(p.value)
+		if err != nil {
+			return nil, err
+		}
+		params[p.key] = value
+	}
+
+	if !f.From.IsZero() {
+		params["dateFrom"] = f.From.Format(defaultDateFormat)
+	}
+	if !f.To.IsZero() {
+		params["dateTo"] = f.To.Format(defaultDateFormat)
+	}
+	return params, nil
+}
+
+// Apply adds the filter to the QueryParams of params, overriding the existing values
+func (f *EventFilter) Apply(params *ListParams) error {
+	queryParams, err := f.QueryParams()
+	if err != nil {
+		return err
+	}
+	if params.QueryParams == nil {
+		params.QueryParams = QueryParams{}
+	}
+	for k, v := range queryParams {
+		params.QueryParams[k] = v
+	}
+	return nil
+}
+
+// enumValue returns the string value of a jsonenums enum
+func enumValue(enum json.Marshaler) (string, error) {
+	b, err := enum.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(string(b), `"`), nil
+}
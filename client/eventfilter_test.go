@@ -0,0 +1,101 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/contactlab/contacthub-sdk-go/enums"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestEventFilterQueryParams(t *testing.T) {
+	eventType, eventContext, mode := enums.ViewedPage, enums.Web, enums.Passive
+	filter := &EventFilter{
+		Type:    &eventType,
+		Context: &eventContext,
+		Mode:    &mode,
+		From:    time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2017, 1, 31, 23, 59, 59, 0, time.UTC),
+	}
+
+	params, err := filter.QueryParams()
+	if err != nil {
+		t.Fatalf("Unexpected error. QueryParams: %v", err)
+	}
+	expected := QueryParams{
+		"type":     "viewedPage",
+		"context":  "WEB",
+		"mode":     "PASSIVE",
+		"dateFrom": "2017-01-01T00:00:00+0000",
+		"dateTo":   "2017-01-31T23:59:59+0000",
+	}
+	if diff := pretty.Compare(params, expected); diff != "" {
+		t.Errorf("QueryParams: invalid params: (-got +expected)\n%s", diff)
+	}
+
+	if params, _ := (&EventFilter{}).QueryParams(); len(params) != 0 {
+		t.Errorf("Expected no params for an empty filter, got %v", params)
+	}
+
+	invalid := &EventFilter{From: filter.To, To: filter.From}
+	if _, err := invalid.QueryParams(); err == nil {
+		t.Error("Expected error for a reversed date range")
+	}
+}
+
+func TestEventIterateWithFilter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQueryStringValue(t, r, "customerId", "my-customer-id")
+		testQueryStringValue(t, r, "type", "loggedIn")
+		testQueryStringValue(t, r, "mode", "ACTIVE")
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		fmt.Fprintf(w, `{"page":{"size":1,"totalElements":2,"totalPages":2,"number":%d},"elements":[{"id":"event-%d","type":"loggedIn","context":"WEB"}]}`, page, page)
+	})
+
+	eventType, mode := enums.LoggedIn, enums.Active
+	params := &ListParams{PageSize: 1}
+	if err := (&EventFilter{Type: &eventType, Mode: &mode}).Apply(params); err != nil {
+		t.Fatalf("Unexpected error. Apply: %v", err)
+	}
+
+	var IDs []string
+	for event, err := range testClient.Events.ListAll(context.Background(), "my-customer-id", params) {
+		if err != nil {
+			t.Fatalf("Unexpected error. Events.ListAll: %v", err)
+		}
+		IDs = append(IDs, event.ID)
+	}
+	if diff := pretty.Compare(IDs, []string{"event-0", "event-1"}); diff != "" {
+		t.Errorf("ListAll: invalid events: (-got +expected)\n%s", diff)
+	}
+}
@@ -0,0 +1,352 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/contactlab/contacthub-sdk-go/enums"
+	"github.com/contactlab/contacthub-sdk-go/nullable"
+)
+
+// ProductProperties contains the properties of the product events,
+// i.e. addedProduct, removedProduct, viewedProduct, addedCompare, removedCompare, addedWishlist and removedWishlist
+type ProductProperties struct {
+	ID               string   `json:"id,omitempty"`
+	SKU              string   `json:"sku,omitempty"`
+	Name             string   `json:"name,omitempty"`
+	Price            float64  `json:"price,omitempty"`
+	ImageURL         string   `json:"imageUrl,omitempty"`
+	LinkURL          string   `json:"linkUrl,omitempty"`
+	ShortDescription string   `json:"shortDescription,omitempty"`
+	Category         []string `json:"category,omitempty"`
+}
+
+func (p *ProductProperties) validate() error {
+	if p.ID == "" && p.SKU == "" {
+		return errors.New("product id or sku is required")
+	}
+	return nil
+}
+
+// OrderProduct is a product of an order or a cart
+type OrderProduct struct {
+	ProductProperties
+	Quantity int     `json:"quantity,omitempty"`
+	Subtotal float64 `json:"subtotal,omitempty"`
+	Tax      float64 `json:"tax,omitempty"`
+	Discount float64 `json:"discount,omitempty"`
+	Coupon   string  `json:"coupon,omitempty"`
+}
+
+// LocalAmount contains the currency of an order amount
+type LocalAmount struct {
+	Currency     string  `json:"currency"`
+	ExchangeRate float64 `json:"exchangeRate,omitempty"`
+}
+
+// Amount contains the amounts of an order or a cart
+type Amount struct {
+	Local    *LocalAmount `json:"local,omitempty"`
+	Total    float64      `json:"total"`
+	Revenue  float64      `json:"revenue,omitempty"`
+	Shipping float64      `json:"shipping,omitempty"`
+	Tax      float64      `json:"tax,omitempty"`
+	Discount float64      `json:"discount,omitempty"`
+}
+
+// OrderProperties contains the properties of the completedOrder events
+type OrderProperties struct {
+	OrderID       string         `json:"orderId"`
+	StoreCode     string         `json:"storeCode,omitempty"`
+	PaymentMethod string         `json:"paymentMethod,omitempty"`
+	Type          string         `json:"type,omitempty"`
+	Amount        *Amount        `json:"amount,omitempty"`
+	Products      []OrderProduct `json:"products,omitempty"`
+}
+
+func (p *OrderProperties) validate() error {
+	if p.OrderID == "" {
+		return errors.New("orderId is required")
+	}
+	return nil
+}
+
+// CartProperties contains the properties of the abandonedCart events
+type CartProperties struct {
+	OrderID          string         `json:"orderId,omitempty"`
+	StoreCode        string         `json:"storeCode,omitempty"`
+	AbandonedCartURL string         `json:"abandonedCartUrl,omitempty"`
+	LastUpdate       *CustomDate    `json:"lastUpdate,omitempty"`
+	Amount           *Amount        `json:"amount,omitempty"`
+	Products         []OrderProduct `json:"products,omitempty"`
+}
+
+// ShipmentProperties contains the properties of the orderShipped events
+type ShipmentProperties struct {
+	OrderID        string `json:"orderId"`
+	StoreCode      string `json:"storeCode,omitempty"`
+	Carrier        string `json:"carrier,omitempty"`
+	TrackingCode   string `json:"trackingCode,omitempty"`
+	TrackingURL    string `json:"trackingUrl,omitempty"`
+	ShippingMethod string `json:"shippingMethod,omitempty"`
+}
+
+func (p *ShipmentProperties) validate() error {
+	if p.OrderID == "" {
+		return errors.New("orderId is required")
+	}
+	return nil
+}
+
+// ReviewProperties contains the properties of the reviewedProduct events
+type ReviewProperties struct {
+	ProductProperties
+	Review string  `json:"review,omitempty"`
+	Rating float64 `json:"rating,omitempty"`
+}
+
+// PageProperties contains the properties of the viewedPage, loggedIn and loggedOut events
+type PageProperties struct {
+	Title   string `json:"title,omitempty"`
+	URL     string `json:"url,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Referer string `json:"referer,omitempty"`
+}
+
+// LinkProperties contains the properties of the clickedLink events
+type LinkProperties struct {
+	URL string `json:"url"`
+}
+
+func (p *LinkProperties) validate() error {
+	if p.URL == "" {
+		return errors.New("url is required")
+	}
+	return nil
+}
+
+// SearchProperties contains the properties of the searched events
+type SearchProperties struct {
+	Keyword     string `json:"keyword"`
+	ResultCount int    `json:"resultCount,omitempty"`
+}
+
+func (p *SearchProperties) validate() error {
+	if p.Keyword == "" {
+		return errors.New("keyword is required")
+	}
+	return nil
+}
+
+// SettingProperties contains the properties of the changedSetting events
+type SettingProperties struct {
+	Setting  string      `json:"setting"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue,omitempty"`
+}
+
+func (p *SettingProperties) validate() error {
+	if p.Setting == "" {
+		return errors.New("setting is required")
+	}
+	return nil
+}
+
+// TicketProperties contains the properties of the openedTicket, closedTicket and repliedTicket events
+type TicketProperties struct {
+	TicketID string   `json:"ticketId,omitempty"`
+	Subject  string   `json:"subject,omitempty"`
+	Text     string   `json:"text,omitempty"`
+	Category []string `json:"category,omitempty"`
+}
+
+// CampaignProperties contains the properties of the campaign events, e.g. campaignSent or campaignLinkClicked
+type CampaignProperties struct {
+	CampaignID      string   `json:"campaignId,omitempty"`
+	CampaignName    string   `json:"campaignName,omitempty"`
+	CampaignSubject string   `json:"campaignSubject,omitempty"`
+	CampaignTags    []string `json:"campaignTags,omitempty"`
+	Channel         string   `json:"channel,omitempty"`
+	// LinkURL is set for campaignLinkClicked
+	LinkURL string `json:"linkUrl,omitempty"`
+	// BounceType is set for campaignBounced
+	BounceType string `json:"bounceType,omitempty"`
+}
+
+// AttendanceProperties contains the properties of the events about the participation in a real-world event,
+// e.g. eventInvited or eventParticipated
+type AttendanceProperties struct {
+	EventID   string      `json:"eventId,omitempty"`
+	EventName string      `json:"eventName,omitempty"`
+	EventDate *CustomDate `json:"eventDate,omitempty"`
+	Location  string      `json:"location,omitempty"`
+}
+
+// FormProperties contains the properties of the formCompiled events
+type FormProperties struct {
+	FormID   string                 `json:"formId,omitempty"`
+	FormName string                 `json:"formName,omitempty"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+}
+
+// ServiceProperties contains the properties of the serviceSubscribed and serviceUnsubscribed events
+type ServiceProperties struct {
+	ServiceID   string      `json:"serviceId,omitempty"`
+	ServiceName string      `json:"serviceName,omitempty"`
+	ServiceType string      `json:"serviceType,omitempty"`
+	StartDate   *CustomDate `json:"startDate,omitempty"`
+	EndDate     *CustomDate `json:"endDate,omitempty"`
+}
+
+// GenericProperties contains the free-form properties of the genericActiveEvent and genericPassiveEvent events
+type GenericProperties map[string]interface{}
+
+// eventPropertiesTypes maps every EventType to the type of its properties
+var eventPropertiesTypes = map[enums.EventType]reflect.Type{
+	enums.AbandonedCart:        reflect.TypeOf(CartProperties{}),
+	enums.AddedCompare:         reflect.TypeOf(ProductProperties{}),
+	enums.AddedProduct:         reflect.TypeOf(ProductProperties{}),
+	enums.AddedWishlist:        reflect.TypeOf(ProductProperties{}),
+	enums.CampaignBlacklisted:  reflect.TypeOf(CampaignProperties{}),
+	enums.CampaignBounced:      reflect.TypeOf(CampaignProperties{}),
+	enums.CampaignLinkClicked:  reflect.TypeOf(CampaignProperties{}),
+	enums.CampaignMarkedSpam:   reflect.TypeOf(CampaignProperties{}),
+	enums.CampaignOpened:       reflect.TypeOf(CampaignProperties{}),
+	enums.CampaignSent:         reflect.TypeOf(CampaignProperties{}),
+	enums.CampaignSubscribed:   reflect.TypeOf(CampaignProperties{}),
+	enums.CampaignUnsubscribed: reflect.TypeOf(CampaignProperties{}),
+	enums.ChangedSetting:       reflect.TypeOf(SettingProperties{}),
+	enums.ClickedLink:          reflect.TypeOf(LinkProperties{}),
+	enums.ClosedTicket:         reflect.TypeOf(TicketProperties{}),
+	enums.CompletedOrder:       reflect.TypeOf(OrderProperties{}),
+	enums.EventConfirmed:       reflect.TypeOf(AttendanceProperties{}),
+	enums.EventDeclined:        reflect.TypeOf(AttendanceProperties{}),
+	enums.EventEligible:        reflect.TypeOf(AttendanceProperties{}),
+	enums.EventInvited:         reflect.TypeOf(AttendanceProperties{}),
+	enums.EventNotShow:         reflect.TypeOf(AttendanceProperties{}),
+	enums.EventNotInvited:      reflect.TypeOf(AttendanceProperties{}),
+	enums.EventParticipated:    reflect.TypeOf(AttendanceProperties{}),
+	enums.FormCompiled:         reflect.TypeOf(FormProperties{}),
+	enums.GenericActiveEvent:   reflect.TypeOf(GenericProperties{}),
+	enums.GenericPassiveEvent:  reflect.TypeOf(GenericProperties{}),
+	enums.LoggedIn:             reflect.TypeOf(PageProperties{}),
+	enums.LoggedOut:            reflect.TypeOf(PageProperties{}),
+	enums.OpenedTicket:         reflect.TypeOf(TicketProperties{}),
+	enums.OrderShipped:         reflect.TypeOf(ShipmentProperties{}),
+	enums.RemovedCompare:       reflect.TypeOf(ProductProperties{}),
+	enums.RemovedProduct:       reflect.TypeOf(ProductProperties{}),
+	enums.RemovedWishlist:      reflect.TypeOf(ProductProperties{}),
+	enums.RepliedTicket:        reflect.TypeOf(TicketProperties{}),
+	enums.ReviewedProduct:      reflect.TypeOf(ReviewProperties{}),
+	enums.Searched:             reflect.TypeOf(SearchProperties{}),
+	enums.ServiceSubscribed:    reflect.TypeOf(ServiceProperties{}),
+	enums.ServiceUnsubscribed:  reflect.TypeOf(ServiceProperties{}),
+	enums.ViewedPage:           reflect.TypeOf(PageProperties{}),
+	enums.ViewedProduct:        reflect.TypeOf(ProductProperties{}),
+}
+
+// NewEvent creates an Event of the given type and context for the customer.
+// The properties must be the typed struct of eventType, e.g. OrderProperties for CompletedOrder,
+// either as a value or as a pointer. Required properties are validated
+func NewEvent(customerID string, eventType enums.EventType, eventContext enums.EventContext, properties interface{}) (*Event, error) {
+	propertiesMap, err := encodeEventProperties(eventType, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &Event{Type: eventType, Context: eventContext, Properties: propertiesMap}
+	if customerID != "" {
+		event.CustomerID = nullable.StringFrom(customerID)
+	}
+	return event, nil
+}
+
+// encodeEventProperties checks properties against eventType and converts them to the generic map
+func encodeEventProperties(eventType enums.EventType, properties interface{}) (map[string]interface{}, error) {
+	expected, ok := eventPropertiesTypes[eventType]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %d", eventType)
+	}
+	value := reflect.ValueOf(properties)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if !value.IsValid() || value.Type() != expected {
+		return nil, fmt.Errorf("invalid properties %T for event type %s, expected %v", properties, eventTypeName(eventType), expected)
+	}
+
+	// Validate on a pointer, as validate has pointer receivers
+	ptr := reflect.New(expected)
+	ptr.Elem().Set(value)
+	if v, ok := ptr.Interface().(interface{ validate() error }); ok {
+		if err := v.validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s properties: %w", eventTypeName(eventType), err)
+		}
+	}
+
+	data, err := json.Marshal(ptr.Interface())
+	if err != nil {
+		return nil, err
+	}
+	propertiesMap := map[string]interface{}{}
+	if err := json.Unmarshal(data, &propertiesMap); err != nil {
+		return nil, err
+	}
+	return propertiesMap, nil
+}
+
+// DecodeProperties returns the properties of the event as a pointer to the typed struct of its type,
+// e.g. *OrderProperties for CompletedOrder
+func (r *EventResponse) DecodeProperties() (interface{}, error) {
+	expected, ok := eventPropertiesTypes[r.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %d", r.Type)
+	}
+	properties := reflect.New(expected).Interface()
+	if err := r.DecodePropertiesInto(properties); err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
+// DecodePropertiesInto decodes the properties of the event into the struct pointed by into
+func (r *EventResponse) DecodePropertiesInto(into interface{}) error {
+	data, err := json.Marshal(r.Properties)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, into)
+}
+
+// eventTypeName returns the API name of eventType, for error messages
+func eventTypeName(eventType enums.EventType) string {
+	name, err := enumValue(eventType)
+	if err != nil {
+		return fmt.Sprint(int(eventType))
+	}
+	return name
+}
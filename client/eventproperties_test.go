@@ -0,0 +1,113 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/contactlab/contacthub-sdk-go/enums"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestEventPropertiesTypes(t *testing.T) {
+	for eventType := enums.AbandonedCart; eventType <= enums.ViewedProduct; eventType++ {
+		if _, ok := eventPropertiesTypes[eventType]; !ok {
+			t.Errorf("Missing properties type for %v", eventTypeName(eventType))
+		}
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	properties := OrderProperties{
+		OrderID: "order-1",
+		Type:    "sale",
+		Amount:  &Amount{Total: 12.5, Local: &LocalAmount{Currency: "EUR"}},
+		Products: []OrderProduct{
+			{ProductProperties: ProductProperties{ID: "p-1", Name: "Board"}, Quantity: 2},
+		},
+	}
+	event, err := NewEvent("my-customer-id", enums.CompletedOrder, enums.Ecommerce, &properties)
+	if err != nil {
+		t.Fatalf("Unexpected error. NewEvent: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"orderId": "order-1",
+		"type":    "sale",
+		"amount":  map[string]interface{}{"total": 12.5, "local": map[string]interface{}{"currency": "EUR"}},
+		"products": []interface{}{
+			map[string]interface{}{"id": "p-1", "name": "Board", "quantity": float64(2)},
+		},
+	}
+	if diff := pretty.Compare(event.Properties, expected); diff != "" {
+		t.Errorf("NewEvent: invalid properties: (-got +expected)\n%s", diff)
+	}
+	if event.CustomerID.String != "my-customer-id" || event.Type != enums.CompletedOrder || event.Context != enums.Ecommerce {
+		t.Errorf("NewEvent: invalid event: %+v", event)
+	}
+}
+
+func TestNewEventInvalid(t *testing.T) {
+	cases := []struct {
+		eventType  enums.EventType
+		properties interface{}
+	}{
+		{enums.CompletedOrder, ProductProperties{ID: "p-1"}},
+		{enums.CompletedOrder, OrderProperties{}},
+		{enums.ViewedProduct, ProductProperties{}},
+		{enums.Searched, nil},
+		{enums.EventType(-1), PageProperties{}},
+	}
+
+	for _, c := range cases {
+		if _, err := NewEvent("", c.eventType, enums.Web, c.properties); err == nil {
+			t.Errorf("Expected error for %v with %T", c.eventType, c.properties)
+		}
+	}
+}
+
+func TestEventDecodeProperties(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/events/my-event-id", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"id":"my-event-id","type":"reviewedProduct","context":"ECOMMERCE","properties":{"id":"p-1","name":"Board","review":"Great","rating":5}}`)
+	})
+
+	event, err := testClient.Events.Get("my-event-id")
+	if err != nil {
+		t.Fatalf("Unexpected error. Events.Get: %v", err)
+	}
+	properties, err := event.DecodeProperties()
+	if err != nil {
+		t.Fatalf("Unexpected error. DecodeProperties: %v", err)
+	}
+
+	expected := &ReviewProperties{ProductProperties: ProductProperties{ID: "p-1", Name: "Board"}, Review: "Great", Rating: 5}
+	if diff := pretty.Compare(properties, expected); diff != "" {
+		t.Errorf("DecodeProperties: invalid properties: (-got +expected)\n%s", diff)
+	}
+}
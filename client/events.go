@@ -23,7 +23,10 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/contactlab/contacthub-sdk-go/enums"
@@ -34,6 +37,8 @@ const (
 	eventBasePath = "events"
 )
 
+var errNilEvent = errors.New("event is required")
+
 // Event represents a Contacthub Event
 type Event struct {
 	CustomerID        *null.String            `json:"customerId,omitempty"`
@@ -43,6 +48,8 @@ type Event struct {
 	BringBackProperty *BringBackProperty      `json:"bringBackProperties,omitempty"`
 	ContextInfo       *map[string]interface{} `json:"contextInfo,omitempty"`
 	Date              *CustomDate             `json:"date,omitempty"`
+	// IdempotencyKey overrides the key computed from the event fields, see Key
+	IdempotencyKey string `json:"-"`
 }
 
 // BringBackProperty represents a ContactHub event BringBackProperty, used to match the event with existing users
@@ -79,8 +86,13 @@ type eventListResponse struct {
 
 // Get returns an individual event by the ContactHub Event ID
 func (s *EventService) Get(ID string) (*EventResponse, error) {
+	return s.GetWithContext(context.Background(), ID)
+}
+
+// GetWithContext is the context-aware version of Get
+func (s *EventService) GetWithContext(ctx context.Context, ID string) (*EventResponse, error) {
 	path := fmt.Sprintf("%s/%s", eventBasePath, ID)
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -94,15 +106,50 @@ func (s *EventService) Get(ID string) (*EventResponse, error) {
 }
 
 // Create creates a new Event on ContactHub
+// The ContextInfo, if set, is validated against the typed struct of the event Context, see SetContextInfo.
+// The idempotency key of the event, if any, is sent in the IdempotencyHeader and, if the client has an
+// IdempotencyStore, ErrDuplicateEvent is returned for the events already created. See Event.Key
 func (s *EventService) Create(event *Event) (*EventResponse, error) {
-	req, err := s.client.NewRequest(http.MethodPost, eventBasePath, event)
+	return s.CreateWithContext(context.Background(), event)
+}
+
+// CreateWithContext is the context-aware version of Create
+func (s *EventService) CreateWithContext(ctx context.Context, event *Event) (*EventResponse, error) {
+	if event == nil {
+		return nil, errNilEvent
+	}
+	if err := event.validate(); err != nil {
+		return nil, err
+	}
+	key, err := event.Key()
 	if err != nil {
 		return nil, err
 	}
+	store := s.client.Config.IdempotencyStore
+	if key == "" {
+		store = nil
+	}
+	if store != nil && !store.Reserve(key) {
+		return nil, ErrDuplicateEvent
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPost, eventBasePath, event)
+	if err != nil {
+		if store != nil {
+			store.Release(key)
+		}
+		return nil, err
+	}
+	if key != "" {
+		req.Header.Set(IdempotencyHeader, key)
+	}
 
 	createdEvent := new(EventResponse)
 	_, err = s.client.Do(req, createdEvent)
 	if err != nil {
+		if store != nil {
+			store.Release(key)
+		}
 		return nil, err
 	}
 
@@ -111,9 +158,14 @@ func (s *EventService) Create(event *Event) (*EventResponse, error) {
 
 // List lists all events for a specified customer
 func (s *EventService) List(customerID string, params *ListParams) ([]EventResponse, PageInfo, error) {
+	return s.ListWithContext(context.Background(), customerID, params)
+}
+
+// ListWithContext is the context-aware version of List
+func (s *EventService) ListWithContext(ctx context.Context, customerID string, params *ListParams) ([]EventResponse, PageInfo, error) {
 	params.preparePagination()
 	params.QueryParams["customerId"] = customerID
-	events, pageInfo, err := s.list(params, eventBasePath)
+	events, pageInfo, err := s.list(ctx, params, eventBasePath)
 	if err != nil {
 		return nil, PageInfo{}, err
 	}
@@ -121,11 +173,23 @@ func (s *EventService) List(customerID string, params *ListParams) ([]EventRespo
 	return events, *pageInfo, err
 }
 
-func (s *EventService) list(params *ListParams, basePath string) ([]EventResponse, *PageInfo, error) {
+// Iterate returns an Iterator over all the events of a customer, starting from params.Page
+func (s *EventService) Iterate(ctx context.Context, customerID string, params *ListParams) *Iterator[EventResponse] {
+	return newIterator(ctx, params, func(ctx context.Context, params *ListParams) ([]EventResponse, PageInfo, error) {
+		return s.ListWithContext(ctx, customerID, params)
+	})
+}
+
+// ListAll returns all the events of a customer as a range-over-func sequence
+func (s *EventService) ListAll(ctx context.Context, customerID string, params *ListParams) iter.Seq2[EventResponse, error] {
+	return s.Iterate(ctx, customerID, params).All()
+}
+
+func (s *EventService) list(ctx context.Context, params *ListParams, basePath string) ([]EventResponse, *PageInfo, error) {
 	path := addQuery(basePath, params.QueryParams)
 
 	List := &eventListResponse{}
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -141,8 +205,13 @@ func (s *EventService) list(params *ListParams, basePath string) ([]EventRespons
 
 // Delete remove an Event from ContactHub
 func (s *EventService) Delete(ID string) error {
+	return s.DeleteWithContext(context.Background(), ID)
+}
+
+// DeleteWithContext is the context-aware version of Delete
+func (s *EventService) DeleteWithContext(ctx context.Context, ID string) error {
 	path := fmt.Sprintf("%s/%s", eventBasePath, ID)
-	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
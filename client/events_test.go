@@ -23,6 +23,8 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -219,3 +221,26 @@ func TestEventGet(t *testing.T) {
 		t.Errorf("Events.Get: invalid value for struct: (-got +expected)\n%s", diff)
 	}
 }
+
+func TestEventCreateWithContextCancelled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected the request not to reach the server")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	event := Event{
+		CustomerID: nullable.StringFrom("aaa"),
+		Type:       enums.AbandonedCart,
+		Properties: map[string]interface{}{},
+		Context:    enums.Ecommerce,
+	}
+	_, err := testClient.Events.CreateWithContext(ctx, &event)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
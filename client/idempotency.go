@@ -0,0 +1,129 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/contactlab/contacthub-sdk-go/enums"
+	"github.com/guregu/null"
+)
+
+// IdempotencyHeader is the request header carrying the idempotency key of the created events
+const IdempotencyHeader = "Idempotency-Key"
+
+// ErrDuplicateEvent is returned when creating an event whose idempotency key was already seen by the IdempotencyStore
+var ErrDuplicateEvent = errors.New("duplicate event")
+
+// Key returns the idempotency key of the event: IdempotencyKey if set, otherwise a hash of
+// its type, context, customer, bring back property, date and properties.
+// Events without a Date are usually stamped by the API, so identical ones may well be distinct occurrences,
+// e.g. two views of the same page: their key is empty and they are never deduplicated
+func (e *Event) Key() (string, error) {
+	if e.IdempotencyKey != "" {
+		return e.IdempotencyKey, nil
+	}
+	if e.Date == nil {
+		return "", nil
+	}
+
+	// Maps are marshaled with sorted keys, so equal events have equal hashes
+	data, err := json.Marshal(struct {
+		Type              enums.EventType        `json:"type"`
+		Context           enums.EventContext     `json:"context"`
+		CustomerID        *null.String           `json:"customerId"`
+		BringBackProperty *BringBackProperty     `json:"bringBackProperties"`
+		Date              *CustomDate            `json:"date"`
+		Properties        map[string]interface{} `json:"properties"`
+	}{e.Type, e.Context, e.CustomerID, e.BringBackProperty, e.Date, e.Properties})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// IdempotencyStore records the idempotency keys of the events created by the client,
+// in order to suppress the duplicates before they are sent
+type IdempotencyStore interface {
+	// Reserve records key, returning false if it was already recorded
+	Reserve(key string) bool
+	// Release forgets key, after the event failed to be created
+	Release(key string)
+}
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore, which forgets the keys after a TTL
+type MemoryIdempotencyStore struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	keys    map[string]time.Time
+	nextGC  time.Time
+	nowFunc func() time.Time
+}
+
+// NewMemoryIdempotencyStore creates a MemoryIdempotencyStore keeping the keys for ttl
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{ttl: ttl, keys: map[string]time.Time{}, nowFunc: time.Now}
+}
+
+// Reserve implements the IdempotencyStore interface
+func (s *MemoryIdempotencyStore) Reserve(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.nowFunc()
+	if now.After(s.nextGC) {
+		// Purge the expired keys at most once per TTL
+		for k, expiry := range s.keys {
+			if !now.Before(expiry) {
+				delete(s.keys, k)
+			}
+		}
+		s.nextGC = now.Add(s.ttl)
+	}
+
+	if expiry, ok := s.keys[key]; ok && now.Before(expiry) {
+		return false
+	}
+	s.keys[key] = now.Add(s.ttl)
+	return true
+}
+
+// Release implements the IdempotencyStore interface
+func (s *MemoryIdempotencyStore) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, key)
+}
+
+// Len returns the number of recorded keys, expired ones included until purged
+func (s *MemoryIdempotencyStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.keys)
+}
@@ -0,0 +1,208 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contactlab/contacthub-sdk-go/enums"
+	"github.com/contactlab/contacthub-sdk-go/nullable"
+)
+
+func TestEventKey(t *testing.T) {
+	date := &CustomDate{time.Date(2017, 8, 1, 20, 23, 9, 0, time.UTC)}
+	newEvent := func(price float64) *Event {
+		return &Event{
+			CustomerID: nullable.StringFrom("my-customer-id"),
+			Type:       enums.ViewedProduct,
+			Context:    enums.Ecommerce,
+			Properties: map[string]interface{}{"id": "p-1", "price": price, "category": []string{"boards"}},
+			Date:       date,
+		}
+	}
+
+	key1, err := newEvent(10).Key()
+	if err != nil {
+		t.Fatalf("Unexpected error. Key: %v", err)
+	}
+	key2, _ := newEvent(10).Key()
+	key3, _ := newEvent(11).Key()
+	if key1 != key2 || key1 == key3 || len(key1) != 64 {
+		t.Errorf("Expected equal keys for equal events only, got %v, %v, %v", key1, key2, key3)
+	}
+
+	event := newEvent(10)
+	event.IdempotencyKey = "my-key"
+	if key, _ := event.Key(); key != "my-key" {
+		t.Errorf("Expected the explicit key, got %v", key)
+	}
+
+	event = newEvent(10)
+	event.Date = nil
+	if key, _ := event.Key(); key != "" {
+		t.Errorf("Expected no key for events without a date, got %v", key)
+	}
+}
+
+// datedEvent is a trackedEvent with a Date, which makes it subject to deduplication
+func datedEvent(n int) *Event {
+	event := trackedEvent(n)
+	event.Date = &CustomDate{time.Date(2017, 8, 1, 20, 23, 9, 0, time.UTC)}
+	return event
+}
+
+func TestEventCreateIdempotency(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	var keys []string
+	recorder := handleTrackedEvents(t, func(n, attempt int) int {
+		if n == 1 && attempt == 1 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}, nil, nil)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(IdempotencyHeader))
+		mu.Unlock()
+	})
+	mockServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r)
+		mux.ServeHTTP(w, r)
+	})
+	testClient.Config.IdempotencyStore = NewMemoryIdempotencyStore(time.Minute)
+
+	event := datedEvent(0)
+	if _, err := testClient.Events.Create(event); err != nil {
+		t.Fatalf("Unexpected error. Events.Create: %v", err)
+	}
+	if _, err := testClient.Events.Create(datedEvent(0)); err != ErrDuplicateEvent {
+		t.Errorf("Expected ErrDuplicateEvent, got %v", err)
+	}
+
+	// A failed event can be sent again
+	if _, err := testClient.Events.Create(datedEvent(1)); !IsServerError(err) {
+		t.Errorf("Expected server error, got %v", err)
+	}
+	if _, err := testClient.Events.Create(datedEvent(1)); err != nil {
+		t.Errorf("Unexpected error. Events.Create: %v", err)
+	}
+
+	// Identical events without a date are distinct occurrences
+	for i := 0; i < 2; i++ {
+		if _, err := testClient.Events.Create(trackedEvent(2)); err != nil {
+			t.Errorf("Unexpected error. Events.Create: %v", err)
+		}
+	}
+
+	expectedKey, _ := event.Key()
+	if len(keys) != 5 || keys[0] != expectedKey || keys[3] != "" || keys[4] != "" {
+		t.Errorf("Expected 3 requests with an idempotency key and 2 without, got %v", keys)
+	}
+	if received := recorder.sorted(); len(received) != 4 {
+		t.Errorf("Expected 4 events created, got %v", received)
+	}
+}
+
+func TestDiskQueueIdempotencyKey(t *testing.T) {
+	setup()
+	defer teardown()
+
+	received := make(chan string, 1)
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get(IdempotencyHeader)
+		w.Write([]byte(`{}`))
+	})
+
+	q, err := testClient.Events.NewDiskQueue(&DiskQueueConfig{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Unexpected error. NewDiskQueue: %v", err)
+	}
+	defer q.Close()
+
+	event := trackedEvent(0)
+	event.IdempotencyKey = "my-key"
+	q.Enqueue(event)
+	flushQueue(t, q)
+
+	if key := <-received; key != "my-key" {
+		t.Errorf("Expected the explicit key, got %v", key)
+	}
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryIdempotencyStore(time.Minute)
+	store.nowFunc = func() time.Time { return now }
+
+	if !store.Reserve("a") || store.Reserve("a") {
+		t.Error("Expected the first reservation only to succeed")
+	}
+	store.Release("a")
+	if !store.Reserve("a") {
+		t.Error("Expected a released key to be reserved again")
+	}
+
+	now = now.Add(30 * time.Second)
+	store.Reserve("b")
+	now = now.Add(40 * time.Second)
+	if !store.Reserve("a") {
+		t.Error("Expected an expired key to be reserved again")
+	}
+	if store.Reserve("b") {
+		t.Error("Expected a key not expired yet to be rejected")
+	}
+
+	now = now.Add(2 * time.Minute)
+	store.Reserve("c")
+	if store.Len() != 1 {
+		t.Errorf("Expected the expired keys to be purged, got %v keys", store.Len())
+	}
+}
+
+func TestEventTrackerDuplicate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleTrackedEvents(t, statusOK, nil, nil)
+	testClient.Config.IdempotencyStore = NewMemoryIdempotencyStore(time.Minute)
+
+	failures := make(chan error, 2)
+	tracker := testClient.Events.NewTracker(&TrackerConfig{
+		Workers:   1,
+		OnFailure: func(event *Event, err error) { failures <- err },
+	})
+	tracker.Track(datedEvent(0))
+	tracker.Track(datedEvent(0))
+	tracker.Close(context.Background())
+
+	if len(failures) != 1 || <-failures != ErrDuplicateEvent {
+		t.Error("Expected the duplicate event to fail with ErrDuplicateEvent")
+	}
+}
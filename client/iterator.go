@@ -0,0 +1,150 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"iter"
+)
+
+// Iterator walks all the pages of a list endpoint, one element at a time:
+//
+//	it := apiClient.Customers.Iterate(ctx, &ListParams{PageSize: 50})
+//	for it.Next() {
+//		customer := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		// Handle the error
+//	}
+type Iterator[T any] struct {
+	ctx      context.Context
+	fetch    func(context.Context, *ListParams) ([]T, PageInfo, error)
+	params   ListParams
+	limit    int
+	count    int
+	items    []T
+	pageInfo *PageInfo
+	value    T
+	err      error
+	// checkpointer saves the progress of the Iterator, see Resume
+	checkpointer *checkpointer
+}
+
+// newIterator creates an Iterator starting from params.Page. The params are copied, so they are not modified
+func newIterator[T any](ctx context.Context, params *ListParams, fetch func(context.Context, *ListParams) ([]T, PageInfo, error)) *Iterator[T] {
+	page := 0
+	if params != nil {
+		page = params.Page
+	}
+	return &Iterator[T]{ctx: ctx, fetch: fetch, params: *copyListParams(params, page)}
+}
+
+// Limit sets the maximum number of elements returned by the Iterator. Zero means no limit
+func (it *Iterator[T]) Limit(limit int) *Iterator[T] {
+	it.limit = limit
+	return it
+}
+
+// Next advances to the next element, fetching the next page if needed.
+// It returns false when there are no more elements, the limit is reached or an error occurred
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil || (it.limit > 0 && it.count >= it.limit) {
+		return false
+	}
+
+	for len(it.items) == 0 {
+		if it.pageInfo != nil {
+			last := !it.pageInfo.HasNextPage() || it.params.Page >= it.pageInfo.TotalPages
+			if it.checkpointer != nil {
+				if err := it.checkpointer.completed(*it.pageInfo, it.params.Page-1, last); err != nil {
+					it.err = err
+					return false
+				}
+			}
+			if last {
+				return false
+			}
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		items, pageInfo, err := it.fetch(it.ctx, &it.params)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if it.checkpointer != nil {
+			if err := it.checkpointer.verify(pageInfo); err != nil {
+				it.err = err
+				return false
+			}
+		}
+		it.items, it.pageInfo = items, &pageInfo
+		it.params.Page++
+	}
+
+	it.value, it.items = it.items[0], it.items[1:]
+	it.count++
+	return true
+}
+
+// Value returns the current element
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error which stopped the Iterator, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// PageInfo returns the pagination info of the last fetched page
+func (it *Iterator[T]) PageInfo() PageInfo {
+	if it.pageInfo == nil {
+		return PageInfo{}
+	}
+	return *it.pageInfo
+}
+
+// All returns the elements as a range-over-func sequence. An error is yielded as the last pair:
+//
+//	for customer, err := range apiClient.Customers.Iterate(ctx, nil).All() {
+//		if err != nil {
+//			// Handle the error
+//		}
+//	}
+func (it *Iterator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next() {
+			if !yield(it.value, nil) {
+				return
+			}
+		}
+		if it.err != nil {
+			var zero T
+			yield(zero, it.err)
+		}
+	}
+}
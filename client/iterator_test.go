@@ -0,0 +1,150 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// handlePages serves totalPages pages of two elements, with IDs like "0-1" (page-index)
+func handlePages(t *testing.T, path string, totalPages int, failPage int) *[]int {
+	requested := &[]int{}
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		*requested = append(*requested, page)
+		if page == failPage {
+			http.Error(w, `{"message":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"page":{"size":2,"totalElements":%d,"totalPages":%d,"number":%d},"elements":[{"id":"%d-0"},{"id":"%d-1"}]}`,
+			totalPages*2, totalPages, page, page, page)
+	})
+	return requested
+}
+
+func TestCustomerIterate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requested := handlePages(t, "/customers", 3, -1)
+
+	params := &ListParams{PageSize: 2}
+	it := testClient.Customers.Iterate(context.Background(), params)
+	var IDs []string
+	for it.Next() {
+		IDs = append(IDs, it.Value().ID)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatalf("Unexpected error. Iterator: %v", err)
+	}
+	if diff := pretty.Compare(IDs, []string{"0-0", "0-1", "1-0", "1-1", "2-0", "2-1"}); diff != "" {
+		t.Errorf("Iterator: invalid elements: (-got +expected)\n%s", diff)
+	}
+	if diff := pretty.Compare(*requested, []int{0, 1, 2}); diff != "" {
+		t.Errorf("Iterator: invalid pages: (-got +expected)\n%s", diff)
+	}
+	if params.QueryParams != nil {
+		t.Error("Expected the params not to be modified")
+	}
+}
+
+func TestCustomerListAllLimit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requested := handlePages(t, "/customers", 3, -1)
+
+	var IDs []string
+	for customer, err := range testClient.Customers.Iterate(context.Background(), nil).Limit(3).All() {
+		if err != nil {
+			t.Fatalf("Unexpected error. ListAll: %v", err)
+		}
+		IDs = append(IDs, customer.ID)
+	}
+
+	if diff := pretty.Compare(IDs, []string{"0-0", "0-1", "1-0"}); diff != "" {
+		t.Errorf("ListAll: invalid elements: (-got +expected)\n%s", diff)
+	}
+	if len(*requested) != 2 {
+		t.Errorf("Expected 2 pages to be fetched, got %v", *requested)
+	}
+}
+
+func TestEventListAllError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handlePages(t, "/events", 3, 1)
+
+	var IDs []string
+	var lastErr error
+	for event, err := range testClient.Events.ListAll(context.Background(), "my-customer-id", nil) {
+		if err != nil {
+			lastErr = err
+			break
+		}
+		IDs = append(IDs, event.ID)
+	}
+
+	if !IsServerError(lastErr) {
+		t.Errorf("Expected the error of the second page, got %v", lastErr)
+	}
+	if diff := pretty.Compare(IDs, []string{"0-0", "0-1"}); diff != "" {
+		t.Errorf("ListAll: invalid elements: (-got +expected)\n%s", diff)
+	}
+}
+
+func TestEventIterateCancel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requested := handlePages(t, "/events", 3, -1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	it := testClient.Events.Iterate(ctx, "my-customer-id", nil)
+	count := 0
+	for it.Next() {
+		count++
+		if count == 2 {
+			cancel()
+		}
+	}
+
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", it.Err())
+	}
+	if count != 2 || len(*requested) != 1 {
+		t.Errorf("Expected the iterator to stop after the first page, got %v elements and pages %v", count, *requested)
+	}
+}
@@ -23,6 +23,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -61,8 +62,13 @@ type JobService struct {
 
 // Get returns an individual job of a customer
 func (s *JobService) Get(customerID, ID string) (*JobResponse, error) {
+	return s.GetWithContext(context.Background(), customerID, ID)
+}
+
+// GetWithContext is the context-aware version of Get
+func (s *JobService) GetWithContext(ctx context.Context, customerID, ID string) (*JobResponse, error) {
 	path := fmt.Sprintf(jobBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -77,8 +83,13 @@ func (s *JobService) Get(customerID, ID string) (*JobResponse, error) {
 
 // Create creates a new Job for the Customer, returns the response
 func (s *JobService) Create(customerID string, job *Job) (*JobResponse, error) {
+	return s.CreateWithContext(context.Background(), customerID, job)
+}
+
+// CreateWithContext is the context-aware version of Create
+func (s *JobService) CreateWithContext(ctx context.Context, customerID string, job *Job) (*JobResponse, error) {
 	path := fmt.Sprintf(jobBasePath, customerID)
-	req, err := s.client.NewRequest(http.MethodPost, path, job)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPost, path, job)
 	if err != nil {
 		return nil, err
 	}
@@ -94,8 +105,13 @@ func (s *JobService) Create(customerID string, job *Job) (*JobResponse, error) {
 
 // Update updates a Job via a put operation
 func (s *JobService) Update(customerID, ID string, job *Job) (*JobResponse, error) {
+	return s.UpdateWithContext(context.Background(), customerID, ID, job)
+}
+
+// UpdateWithContext is the context-aware version of Update
+func (s *JobService) UpdateWithContext(ctx context.Context, customerID, ID string, job *Job) (*JobResponse, error) {
 	path := fmt.Sprintf(jobBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodPut, path, job)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPut, path, job)
 	if err != nil {
 		return nil, err
 	}
@@ -111,8 +127,13 @@ func (s *JobService) Update(customerID, ID string, job *Job) (*JobResponse, erro
 
 // Delete deletes a Job
 func (s *JobService) Delete(customerID, ID string) error {
+	return s.DeleteWithContext(context.Background(), customerID, ID)
+}
+
+// DeleteWithContext is the context-aware version of Delete
+func (s *JobService) DeleteWithContext(ctx context.Context, customerID, ID string) error {
 	path := fmt.Sprintf(jobBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
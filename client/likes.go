@@ -23,6 +23,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -55,8 +56,13 @@ type LikeService struct {
 
 // Get returns an individual like of a customer
 func (s *LikeService) Get(customerID, ID string) (*LikeResponse, error) {
+	return s.GetWithContext(context.Background(), customerID, ID)
+}
+
+// GetWithContext is the context-aware version of Get
+func (s *LikeService) GetWithContext(ctx context.Context, customerID, ID string) (*LikeResponse, error) {
 	path := fmt.Sprintf(likeBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -71,8 +77,13 @@ func (s *LikeService) Get(customerID, ID string) (*LikeResponse, error) {
 
 // Create creates a new Like for the Customer, returns the response
 func (s *LikeService) Create(customerID string, like *Like) (*LikeResponse, error) {
+	return s.CreateWithContext(context.Background(), customerID, like)
+}
+
+// CreateWithContext is the context-aware version of Create
+func (s *LikeService) CreateWithContext(ctx context.Context, customerID string, like *Like) (*LikeResponse, error) {
 	path := fmt.Sprintf(likeBasePath, customerID)
-	req, err := s.client.NewRequest(http.MethodPost, path, like)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPost, path, like)
 	if err != nil {
 		return nil, err
 	}
@@ -88,8 +99,13 @@ func (s *LikeService) Create(customerID string, like *Like) (*LikeResponse, erro
 
 // Update updates a Like via a put operation
 func (s *LikeService) Update(customerID, ID string, like *Like) (*LikeResponse, error) {
+	return s.UpdateWithContext(context.Background(), customerID, ID, like)
+}
+
+// UpdateWithContext is the context-aware version of Update
+func (s *LikeService) UpdateWithContext(ctx context.Context, customerID, ID string, like *Like) (*LikeResponse, error) {
 	path := fmt.Sprintf(likeBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodPut, path, like)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPut, path, like)
 	if err != nil {
 		return nil, err
 	}
@@ -105,8 +121,13 @@ func (s *LikeService) Update(customerID, ID string, like *Like) (*LikeResponse,
 
 // Delete deletes a Like
 func (s *LikeService) Delete(customerID, ID string) error {
+	return s.DeleteWithContext(context.Background(), customerID, ID)
+}
+
+// DeleteWithContext is the context-aware version of Delete
+func (s *LikeService) DeleteWithContext(ctx context.Context, customerID, ID string) error {
 	path := fmt.Sprintf(likeBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,180 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// defaultRedactFields are the JSON fields which are always redacted from the logged bodies
+var defaultRedactFields = []string{"password"}
+
+// redactHeaders are the HTTP headers which are never logged in clear
+var redactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// callLogger logs the API calls performed by a Client
+type callLogger struct {
+	logger       *slog.Logger
+	bodies       bool
+	redactFields map[string]bool
+}
+
+// newCallLogger creates the logger configured by config, or nil if logging is disabled.
+// Debug without a LogHandler logs everything, bodies included, to stdout
+func newCallLogger(config *Config) *callLogger {
+	handler := config.LogHandler
+	bodies := config.LogBodies
+	if handler == nil {
+		if !config.Debug {
+			return nil
+		}
+		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})
+		bodies = true
+	}
+
+	redactFields := map[string]bool{}
+	for _, field := range append(defaultRedactFields, config.RedactFields...) {
+		redactFields[strings.ToLower(field)] = true
+	}
+
+	return &callLogger{logger: slog.New(handler), bodies: bodies, redactFields: redactFields}
+}
+
+// log logs a completed call: API errors are logged as warnings, transport errors as errors
+func (l *callLogger) log(call *Call, resp *http.Response, respBody []byte, err error, duration time.Duration) {
+	ctx := call.Request.Context()
+	level := slog.LevelDebug
+	attrs := []slog.Attr{
+		slog.String("method", call.Method),
+		slog.String("path", call.Path),
+		slog.Duration("duration", duration),
+	}
+
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	if err != nil {
+		level = slog.LevelError
+		// The errors carrying the request URL are not logged as they are, since its query may contain
+		// personal data, e.g. the email of a customer lookup
+		var errorResponse *ErrorResponse
+		var urlErr *url.Error
+		switch {
+		case errors.As(err, &errorResponse):
+			level = slog.LevelWarn
+			attrs = append(attrs,
+				slog.String("logref", errorResponse.Logref),
+				slog.String("error", errorResponse.Message),
+			)
+			if len(errorResponse.Errors) > 0 {
+				attrs = append(attrs, slog.Any("field_errors", errorResponse.FieldErrors()))
+			}
+		case errors.As(err, &urlErr):
+			attrs = append(attrs, slog.String("error", urlErr.Err.Error()))
+		default:
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+	}
+
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	if l.bodies {
+		attrs = append(attrs,
+			slog.Any("request_headers", l.redactHeaders(call.Request.Header)),
+			slog.String("request_body", string(l.redactBody(call.Body))),
+		)
+		if resp != nil {
+			attrs = append(attrs, slog.String("response_body", string(l.redactBody(respBody))))
+		}
+	}
+
+	l.logger.LogAttrs(ctx, level, "contacthub API call", attrs...)
+}
+
+// bufferBody reads the whole response body, replacing it with an in-memory copy
+func bufferBody(resp *http.Response) []byte {
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+	return data
+}
+
+func (l *callLogger) redactHeaders(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, redacted)
+		}
+	}
+	return header
+}
+
+// redactBody masks the sensitive fields of a JSON body. Non-JSON bodies are returned as they are
+func (l *callLogger) redactBody(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return body
+	}
+	redactedBody, err := json.Marshal(l.redactValue(data))
+	if err != nil {
+		return body
+	}
+	return redactedBody
+}
+
+func (l *callLogger) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if l.redactFields[strings.ToLower(key)] && field != nil {
+				v[key] = redacted
+			} else {
+				v[key] = l.redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = l.redactValue(v[i])
+		}
+	}
+	return value
+}
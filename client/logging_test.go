@@ -0,0 +1,183 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/contactlab/contacthub-sdk-go/nullable"
+)
+
+func setupLogging(bodies bool) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	testClient.Config.LogHandler = slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	testClient.Config.LogBodies = bodies
+	testClient.Config.RedactFields = []string{"email"}
+	testClient.logger = newCallLogger(testClient.Config)
+	return buf
+}
+
+func decodeLogRecord(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	record := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Invalid log record %v: %v", buf.String(), err)
+	}
+	return record
+}
+
+func TestLogCall(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/my-customer-id", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"my-customer-id"}`)
+	})
+
+	buf := setupLogging(false)
+	if _, err := testClient.Customers.Get("my-customer-id"); err != nil {
+		t.Fatalf("Unexpected error. Customers.Get: %v", err)
+	}
+
+	record := decodeLogRecord(t, buf)
+	if record["level"] != "DEBUG" || record["method"] != "GET" || record["path"] != "customers/my-customer-id" || record["status"] != float64(200) {
+		t.Errorf("Unexpected log record: %v", record)
+	}
+	if _, ok := record["duration"]; !ok {
+		t.Error("Expected the call duration")
+	}
+	if _, ok := record["response_body"]; ok {
+		t.Error("Expected no bodies")
+	}
+}
+
+func TestLogErrorResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/my-customer-id", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"customer not found","logref":"my-logref"}`)
+	})
+
+	buf := setupLogging(true)
+	if _, err := testClient.Customers.Get("my-customer-id"); err == nil {
+		t.Fatal("Expected error.")
+	}
+
+	record := decodeLogRecord(t, buf)
+	if record["level"] != "WARN" || record["logref"] != "my-logref" || record["status"] != float64(404) {
+		t.Errorf("Unexpected log record: %v", record)
+	}
+	if !strings.Contains(record["response_body"].(string), "customer not found") {
+		t.Errorf("Expected the response body, got %v", record["response_body"])
+	}
+}
+
+func TestLogErrorQuery(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"message":"invalid query","logref":"my-logref","errors":[{"message":"unknown field","path":"/query"}]}`)
+	})
+
+	buf := setupLogging(false)
+	if _, err := testClient.Customers.FindByEmail("john@example.com"); err == nil {
+		t.Fatal("Expected error.")
+	}
+	record := decodeLogRecord(t, buf)
+	if record["error"] != "invalid query" || record["field_errors"] == nil {
+		t.Errorf("Unexpected log record: %v", record)
+	}
+	if strings.Contains(buf.String(), "john") {
+		t.Errorf("Expected no query values in the log record, got %v", buf.String())
+	}
+
+	// Transport errors
+	buf.Reset()
+	mockServer.Close()
+	if _, err := testClient.Customers.FindByEmail("john@example.com"); err == nil {
+		t.Fatal("Expected error.")
+	}
+	if record := decodeLogRecord(t, buf); record["level"] != "ERROR" {
+		t.Errorf("Unexpected log record: %v", record)
+	}
+
+	if strings.Contains(buf.String(), "john") {
+		t.Errorf("Expected no query values in the log record, got %v", buf.String())
+	}
+}
+
+func TestLogRedaction(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"my-customer-id","base":{"credential":{"username":"john","password":"secret"},"contacts":{"email":"john@example.com"}}}`)
+	})
+
+	buf := setupLogging(true)
+	testClient.Use(func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			call.Request.Header.Set("Authorization", "Bearer fakeapikey")
+			return next(call)
+		}
+	})
+
+	customer := Customer{
+		BaseProperties: &BaseProperties{
+			Credential: &Credential{
+				Username: nullable.StringFrom("john"),
+				Password: nullable.StringFrom("secret"),
+			},
+		},
+	}
+	if _, err := testClient.Customers.Create(&customer); err != nil {
+		t.Fatalf("Unexpected error. Customers.Create: %v", err)
+	}
+
+	for _, leak := range []string{"secret", "fakeapikey", "john@example.com"} {
+		if strings.Contains(buf.String(), leak) {
+			t.Errorf("Expected '%v' to be redacted: %v", leak, buf.String())
+		}
+	}
+	if !strings.Contains(buf.String(), "john") {
+		t.Errorf("Expected the non-sensitive fields to be logged: %v", buf.String())
+	}
+}
+
+func TestNoLogging(t *testing.T) {
+	if logger := newCallLogger(&Config{}); logger != nil {
+		t.Error("Expected logging to be disabled by default")
+	}
+	if logger := newCallLogger(&Config{Debug: true}); logger == nil || !logger.bodies {
+		t.Error("Expected Debug to log bodies")
+	}
+}
@@ -0,0 +1,145 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrAmbiguous is matched by AmbiguousCustomerError via errors.Is
+var ErrAmbiguous = errors.New("ambiguous lookup")
+
+// CustomerNotFoundError is returned by the lookup helpers when no customer matches.
+// It matches ErrNotFound via errors.Is
+type CustomerNotFoundError struct {
+	Field string
+	Value string
+}
+
+func (e *CustomerNotFoundError) Error() string {
+	return fmt.Sprintf("no customer found with %s %q", e.Field, e.Value)
+}
+
+// Is matches ErrNotFound
+func (e *CustomerNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// AmbiguousCustomerError is returned by the lookup helpers when more than one customer matches.
+// It matches ErrAmbiguous via errors.Is
+type AmbiguousCustomerError struct {
+	Field string
+	Value string
+	// Count is the total number of matching customers
+	Count int
+	// Customers contains the first matching customers
+	Customers []CustomerResponse
+}
+
+func (e *AmbiguousCustomerError) Error() string {
+	return fmt.Sprintf("%d customers found with %s %q", e.Count, e.Field, e.Value)
+}
+
+// Is matches ErrAmbiguous
+func (e *AmbiguousCustomerError) Is(target error) bool {
+	return target == ErrAmbiguous
+}
+
+// FindByExternalID returns the customer of the default Node with the given externalId
+func (s *CustomerService) FindByExternalID(externalID string) (*CustomerResponse, error) {
+	return s.FindByExternalIDWithContext(context.Background(), externalID)
+}
+
+// FindByExternalIDWithContext is the context-aware version of FindByExternalID
+func (s *CustomerService) FindByExternalIDWithContext(ctx context.Context, externalID string) (*CustomerResponse, error) {
+	return s.find(ctx, "externalId", externalID, &CustomerQuery{ExternalID: externalID})
+}
+
+// FindByEmail returns the customer of the default Node with the given base.contacts.email
+func (s *CustomerService) FindByEmail(email string) (*CustomerResponse, error) {
+	return s.FindByEmailWithContext(context.Background(), email)
+}
+
+// FindByEmailWithContext is the context-aware version of FindByEmail
+func (s *CustomerService) FindByEmailWithContext(ctx context.Context, email string) (*CustomerResponse, error) {
+	where := BaseField("contacts.email").Equals(email)
+	return s.find(ctx, "email", email, &CustomerQuery{Where: &where})
+}
+
+// FindByUsername returns the customer of the default Node with the given base.credential.username
+func (s *CustomerService) FindByUsername(username string) (*CustomerResponse, error) {
+	return s.FindByUsernameWithContext(context.Background(), username)
+}
+
+// FindByUsernameWithContext is the context-aware version of FindByUsername
+func (s *CustomerService) FindByUsernameWithContext(ctx context.Context, username string) (*CustomerResponse, error) {
+	where := BaseField("credential.username").Equals(username)
+	return s.find(ctx, "username", username, &CustomerQuery{Where: &where})
+}
+
+// FindBySession returns the customer of the default Node with a session with the given value
+func (s *CustomerService) FindBySession(value string) (*CustomerResponse, error) {
+	return s.FindBySessionWithContext(context.Background(), value)
+}
+
+// FindBySessionWithContext is the context-aware version of FindBySession
+func (s *CustomerService) FindBySessionWithContext(ctx context.Context, value string) (*CustomerResponse, error) {
+	where := Field("sessions.value").Equals(value)
+	return s.find(ctx, "session", value, &CustomerQuery{Where: &where})
+}
+
+// find lists the customers matching query, expecting exactly one of them.
+// An empty value is rejected, as the query would not filter the customers at all
+func (s *CustomerService) find(ctx context.Context, field, value string, query *CustomerQuery) (*CustomerResponse, error) {
+	if value == "" {
+		return nil, fmt.Errorf("%s is required", field)
+	}
+	params := &ListParams{PageSize: 2}
+	if err := query.Apply(params); err != nil {
+		return nil, err
+	}
+
+	customers, pageInfo, err := s.ListWithContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(customers) == 0:
+		return nil, &CustomerNotFoundError{Field: field, Value: value}
+	case len(customers) > 1 || pageInfo.TotalElements > 1:
+		count := pageInfo.TotalElements
+		if count < len(customers) {
+			count = len(customers)
+		}
+		return nil, &AmbiguousCustomerError{Field: field, Value: value, Count: count, Customers: customers}
+	}
+	return &customers[0], nil
+}
+
+// IsAmbiguous checks whether err is an AmbiguousCustomerError
+func IsAmbiguous(err error) bool {
+	return errors.Is(err, ErrAmbiguous)
+}
@@ -0,0 +1,136 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// handleLookup serves the customers listing, returning the given number of matches
+func handleLookup(t *testing.T, matches int) *http.Request {
+	received := new(http.Request)
+	mux.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQueryStringValue(t, r, "size", "2")
+		*received = *r
+
+		elements := make([]string, 0, 2)
+		for i := 0; i < matches && i < 2; i++ {
+			elements = append(elements, fmt.Sprintf(`{"id":"customer-%d","nodeId":"fakenodeid"}`, i))
+		}
+		fmt.Fprintf(w, `{"page":{"size":2,"totalElements":%d,"totalPages":%d,"number":0},"elements":[%s]}`,
+			matches, (matches+1)/2, strings.Join(elements, ","))
+	})
+	return received
+}
+
+func TestCustomerFind(t *testing.T) {
+	cases := []struct {
+		find          func(*CustomerService, string) (*CustomerResponse, error)
+		expectedParam string
+		expectedValue string
+	}{
+		{(*CustomerService).FindByExternalID, "externalId", "value"},
+		{(*CustomerService).FindByEmail, "query", `"attribute":"base.contacts.email","operator":"EQUALS","value":"value"`},
+		{(*CustomerService).FindByUsername, "query", `"attribute":"base.credential.username","operator":"EQUALS","value":"value"`},
+		{(*CustomerService).FindBySession, "query", `"attribute":"sessions.value","operator":"EQUALS","value":"value"`},
+	}
+
+	for _, c := range cases {
+		setup()
+		received := handleLookup(t, 1)
+		customer, err := c.find(testClient.Customers, "value")
+		teardown()
+
+		if err != nil {
+			t.Errorf("Unexpected error. Find: %v", err)
+			continue
+		}
+		if customer.ID != "customer-0" {
+			t.Errorf("Expected customer-0, got %v", customer.ID)
+		}
+		if value := received.URL.Query().Get(c.expectedParam); !strings.Contains(value, c.expectedValue) {
+			t.Errorf("Expected %v to contain %v, got %v", c.expectedParam, c.expectedValue, value)
+		}
+	}
+}
+
+func TestCustomerFindNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleLookup(t, 0)
+	_, err := testClient.Customers.FindByEmail("john@example.com")
+
+	var notFound *CustomerNotFoundError
+	if !errors.As(err, &notFound) || !IsNotFound(err) {
+		t.Fatalf("Expected CustomerNotFoundError, got %v", err)
+	}
+	if notFound.Field != "email" || notFound.Value != "john@example.com" {
+		t.Errorf("Unexpected not found error: %+v", notFound)
+	}
+}
+
+func TestCustomerFindEmpty(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request for an empty value")
+	})
+
+	lookups := []func(*CustomerService, string) (*CustomerResponse, error){
+		(*CustomerService).FindByExternalID,
+		(*CustomerService).FindByEmail,
+		(*CustomerService).FindByUsername,
+		(*CustomerService).FindBySession,
+	}
+	for _, lookup := range lookups {
+		if customer, err := lookup(testClient.Customers, ""); err == nil || IsNotFound(err) {
+			t.Errorf("Expected error for an empty value, got %v, %v", customer, err)
+		}
+	}
+}
+
+func TestCustomerFindAmbiguous(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleLookup(t, 5)
+	_, err := testClient.Customers.FindByExternalID("ext")
+
+	var ambiguous *AmbiguousCustomerError
+	if !errors.As(err, &ambiguous) || !IsAmbiguous(err) {
+		t.Fatalf("Expected AmbiguousCustomerError, got %v", err)
+	}
+	if ambiguous.Count != 5 || len(ambiguous.Customers) != 2 {
+		t.Errorf("Expected 5 matches, 2 returned, got %v, %v", ambiguous.Count, len(ambiguous.Customers))
+	}
+	if IsNotFound(err) {
+		t.Error("Expected ambiguous error not to match ErrNotFound")
+	}
+}
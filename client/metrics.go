@@ -0,0 +1,52 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Metrics receives the usage metrics of a Client.
+// The service and method labels are the ones of the Call, e.g. "Customers" and "Update".
+// See the metrics package for the expvar and Prometheus implementations
+type Metrics interface {
+	// ObserveCall is called once per API call, after any retry
+	ObserveCall(service, method, statusClass string, duration time.Duration)
+	// ObserveRetry is called for each retried attempt
+	ObserveRetry(service, method string)
+	// ObserveRateLimitWait is called whenever a request is delayed by the client rate limiter
+	ObserveRateLimitWait(service, method string, wait time.Duration)
+}
+
+// StatusClassError is the status class of the calls which got no response at all
+const StatusClassError = "error"
+
+// statusClass returns the status class of a response, e.g. "2xx"
+func statusClass(resp *http.Response) string {
+	if resp == nil {
+		return StatusClassError
+	}
+	return fmt.Sprintf("%dxx", resp.StatusCode/100)
+}
@@ -0,0 +1,106 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+type recordedMetrics struct {
+	mu             sync.Mutex
+	calls          []string
+	retries        []string
+	rateLimitWaits []string
+}
+
+func (m *recordedMetrics) ObserveCall(service, method, statusClass string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, service+"."+method+" "+statusClass)
+}
+
+func (m *recordedMetrics) ObserveRetry(service, method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries = append(m.retries, service+"."+method)
+}
+
+func (m *recordedMetrics) ObserveRateLimitWait(service, method string, wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rateLimitWaits = append(m.rateLimitWaits, service+"."+method)
+}
+
+func TestMetrics(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/customers/my-customer-id", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			http.Error(w, `{"message":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"id":"my-customer-id"}`)
+	})
+
+	metrics := &recordedMetrics{}
+	testClient.Config.Metrics = metrics
+	testClient.Config.RetryPolicy = testRetryPolicy()
+	// The bucket never runs out of tokens, so the only waits are the pauses set before each call
+	testClient.limiter, _ = newRateLimiter(&RateLimit{RequestsPerSecond: 1000, Burst: 10})
+
+	testClient.limiter.pausedUntil = time.Now().Add(10 * time.Millisecond)
+	testClient.Customers.Get("my-customer-id")
+	testClient.limiter.pausedUntil = time.Now().Add(10 * time.Millisecond)
+	testClient.Customers.Delete("my-customer-id")
+
+	expected := &recordedMetrics{
+		calls:          []string{"Customers.Get 2xx", "Customers.Delete 4xx"},
+		retries:        []string{"Customers.Get"},
+		rateLimitWaits: []string{"Customers.Get", "Customers.Delete"},
+	}
+	if diff := pretty.Compare(metrics, expected); diff != "" {
+		t.Errorf("Metrics: invalid values: (-got +expected)\n%s", diff)
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	if class := statusClass(&http.Response{StatusCode: 503}); class != "5xx" {
+		t.Errorf("Expected 5xx, got %v", class)
+	}
+	if class := statusClass(nil); class != StatusClassError {
+		t.Errorf("Expected %v, got %v", StatusClassError, class)
+	}
+}
@@ -0,0 +1,164 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Call represents a single API call going through the Client middleware chain
+type Call struct {
+	// Method is the HTTP method of the call
+	Method string
+	// Path is the path of the call, relative to the Client BaseURL
+	Path string
+	// Service is the name of the service performing the call, e.g. "Customers"
+	Service string
+	// Operation is the name of the service method performing the call, e.g. "Update"
+	Operation string
+	// Request is the HTTP request about to be sent. Middlewares can mutate it, e.g. adding headers
+	Request *http.Request
+	// Body is the JSON encoded request body. Middlewares can replace it
+	Body []byte
+	// Into is where the response body is decoded, once the call is performed
+	Into interface{}
+}
+
+// Handler performs an API call
+type Handler func(call *Call) (*http.Response, error)
+
+// Middleware wraps a Handler, in order to run some code before and/or after the API call.
+// A middleware can short-circuit the call by returning without invoking next.
+// API errors are returned by next as *ErrorResponse
+type Middleware func(next Handler) Handler
+
+// Use appends middlewares to the chain of the Client.
+// Middlewares are run in the order they are added, the first one being the outermost.
+// Use is not safe for concurrent use with Do, so it should be called right after New
+func (c *Client) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+// newCall builds the Call for a request created by NewRequest
+func (c *Client) newCall(req *http.Request, into interface{}) (*Call, error) {
+	call := &Call{
+		Method:  req.Method,
+		Path:    strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, c.BaseURL.Path), "/"),
+		Request: req,
+		Into:    into,
+	}
+	call.Service, call.Operation = operationName(call.Method, call.Path)
+
+	body := req.Body
+	if req.GetBody != nil {
+		var err error
+		if body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	if body != nil && body != http.NoBody {
+		defer body.Close()
+		var err error
+		if call.Body, err = ioutil.ReadAll(body); err != nil {
+			return nil, err
+		}
+	}
+	return call, nil
+}
+
+// serviceNames maps the API resources to the names of the services
+var serviceNames = map[string]string{
+	"customers":     "Customers",
+	"events":        "Events",
+	"sessions":      "Sessions",
+	"likes":         "Likes",
+	"educations":    "Educations",
+	"jobs":          "Jobs",
+	"subscriptions": "Subscriptions",
+}
+
+// operationName infers the service and the method names from the method and the path of a call,
+// e.g. "PATCH customers/ID" is Customers.Update
+func operationName(method, path string) (string, string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	// Sub-resources of a customer are handled by their own service
+	resource, hasID := segments[0], len(segments) > 1
+	if len(segments) > 2 {
+		resource, hasID = segments[2], len(segments) > 3
+	}
+
+	service, ok := serviceNames[resource]
+	if !ok {
+		service = capitalize(resource)
+	}
+
+	switch method {
+	case http.MethodGet:
+		if hasID {
+			return service, "Get"
+		}
+		return service, "List"
+	case http.MethodPost:
+		return service, "Create"
+	case http.MethodPut, http.MethodPatch:
+		return service, "Update"
+	case http.MethodDelete:
+		return service, "Delete"
+	}
+	return service, capitalize(strings.ToLower(method))
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// handler returns the final handler wrapped by all the middlewares
+func (c *Client) handler() Handler {
+	handler := Handler(c.do)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+	return handler
+}
+
+// setBody replaces the body of req, keeping it rewindable for retries
+func setBody(req *http.Request, body []byte) {
+	req.ContentLength = int64(len(body))
+	if len(body) == 0 {
+		req.Body = http.NoBody
+		req.GetBody = func() (io.ReadCloser, error) { return http.NoBody, nil }
+		return
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+}
@@ -0,0 +1,184 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/contactlab/contacthub-sdk-go/nullable"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/my-customer-id", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"my-customer-id"}`)
+	})
+
+	var trace []string
+	tracer := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(call *Call) (*http.Response, error) {
+				trace = append(trace, name+" before")
+				resp, err := next(call)
+				trace = append(trace, name+" after")
+				return resp, err
+			}
+		}
+	}
+	testClient.Use(tracer("first"), tracer("second"))
+	testClient.Use(tracer("third"))
+
+	if _, err := testClient.Customers.Get("my-customer-id"); err != nil {
+		t.Fatalf("Unexpected error. Customers.Get: %v", err)
+	}
+
+	expected := []string{"first before", "second before", "third before", "third after", "second after", "first after"}
+	if diff := pretty.Compare(trace, expected); diff != "" {
+		t.Errorf("Middleware: invalid order: (-got +expected)\n%s", diff)
+	}
+}
+
+func TestMiddlewareCall(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/my-customer-id", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Audit") != "yes" {
+			t.Error("Expected the header added by the middleware")
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if trimmedBody := strings.TrimSpace(string(body)); trimmedBody != `{"enabled":false}` {
+			t.Errorf("Expected the body replaced by the middleware, got %v", trimmedBody)
+		}
+		fmt.Fprint(w, `{"id":"my-customer-id","enabled":false}`)
+	})
+
+	var seen Call
+	var decoded *CustomerResponse
+	testClient.Use(func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			seen = *call
+			call.Request.Header.Set("X-Audit", "yes")
+			call.Body = []byte(`{"enabled":false}`)
+			resp, err := next(call)
+			decoded = call.Into.(*CustomerResponse)
+			return resp, err
+		}
+	})
+
+	customer := Customer{Enabled: nullable.BoolFrom(true)}
+	if _, err := testClient.Customers.Update("my-customer-id", &customer); err != nil {
+		t.Fatalf("Unexpected error. Customers.Update: %v", err)
+	}
+
+	if seen.Method != http.MethodPatch || seen.Path != "customers/my-customer-id" {
+		t.Errorf("Unexpected call: %v %v", seen.Method, seen.Path)
+	}
+	if trimmedBody := strings.TrimSpace(string(seen.Body)); trimmedBody != `{"enabled":true}` {
+		t.Errorf("Expected the encoded body, got %v", trimmedBody)
+	}
+	if decoded == nil || decoded.ID != "my-customer-id" {
+		t.Errorf("Expected the decoded response, got %v", decoded)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/my-customer-id", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected the request not to reach the server")
+	})
+
+	testClient.Use(func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			call.Into.(*CustomerResponse).ID = "cached"
+			return nil, nil
+		}
+	})
+
+	customer, err := testClient.Customers.Get("my-customer-id")
+	if err != nil {
+		t.Fatalf("Unexpected error. Customers.Get: %v", err)
+	}
+	if customer.ID != "cached" {
+		t.Errorf("Expected the short-circuited response, got %v", customer.ID)
+	}
+}
+
+func TestMiddlewareErrorResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/my-customer-id", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"customer not found","logref":"my-logref"}`)
+	})
+
+	var logref string
+	testClient.Use(func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			resp, err := next(call)
+			if errorResponse, ok := err.(*ErrorResponse); ok {
+				logref = errorResponse.Logref
+			}
+			return resp, err
+		}
+	})
+
+	if _, err := testClient.Customers.Get("my-customer-id"); err == nil {
+		t.Error("Expected error.")
+	}
+	if logref != "my-logref" {
+		t.Errorf("Expected the middleware to see the ErrorResponse, got logref '%v'", logref)
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	cases := []struct {
+		method, path, service, operation string
+	}{
+		{http.MethodGet, "customers", "Customers", "List"},
+		{http.MethodGet, "customers/ID", "Customers", "Get"},
+		{http.MethodPatch, "customers/ID", "Customers", "Update"},
+		{http.MethodPost, "events", "Events", "Create"},
+		{http.MethodDelete, "events/ID", "Events", "Delete"},
+		{http.MethodGet, "customers/ID/sessions", "Sessions", "List"},
+		{http.MethodPut, "customers/ID/likes/ID", "Likes", "Update"},
+		{http.MethodHead, "other", "Other", "Head"},
+	}
+
+	for _, c := range cases {
+		service, operation := operationName(c.method, c.path)
+		if service != c.service || operation != c.operation {
+			t.Errorf("%v %v: expected %v.%v, got %v.%v", c.method, c.path, c.service, c.operation, service, operation)
+		}
+	}
+}
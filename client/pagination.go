@@ -27,6 +27,9 @@ import "strconv"
 // DefaultPageSize is the default page size for pagination. Max is 50.
 const DefaultPageSize int = 20
 
+// maxPageSize is the maximum page size accepted by the API
+const maxPageSize int = 50
+
 // PageInfo contains the pagination info from list endpoints
 type PageInfo struct {
 	Size                    int `json:"size"`
@@ -0,0 +1,196 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+const (
+	// DefaultParallelWorkers is the default number of pages fetched concurrently
+	DefaultParallelWorkers = 4
+)
+
+// ParallelOptions configures the concurrent fetching of the pages of a list endpoint
+type ParallelOptions struct {
+	// Workers is the number of pages fetched concurrently. Defaults to DefaultParallelWorkers
+	Workers int
+	// Buffer is the maximum number of pages fetched ahead of the consumer, in flight ones included.
+	// Defaults to twice the Workers
+	Buffer int
+	// Unordered delivers the elements as soon as their page is fetched, instead of in page order
+	Unordered bool
+}
+
+func (o *ParallelOptions) withDefaults() ParallelOptions {
+	options := ParallelOptions{}
+	if o != nil {
+		options = *o
+	}
+	if options.Workers < 1 {
+		options.Workers = DefaultParallelWorkers
+	}
+	if options.Buffer < options.Workers {
+		options.Buffer = 2 * options.Workers
+	}
+	return options
+}
+
+// ListParallel returns all the customers matching params as a range-over-func sequence, like ListAll,
+// but the pages following the first one are fetched concurrently.
+// Requests still go through the client rate limiter, if any
+func (s *CustomerService) ListParallel(ctx context.Context, params *ListParams, options *ParallelOptions) iter.Seq2[CustomerResponse, error] {
+	return listParallel(ctx, params, options, s.ListWithContext)
+}
+
+// copyListParams returns a copy of params for the given page, since list methods modify their params
+func copyListParams(params *ListParams, page int) *ListParams {
+	newParams := &ListParams{Page: page, QueryParams: QueryParams{}}
+	if params != nil {
+		newParams.PageSize = params.PageSize
+		for k, v := range params.QueryParams {
+			newParams.QueryParams[k] = v
+		}
+	}
+	return newParams
+}
+
+type pageResult[T any] struct {
+	page  int
+	items []T
+	err   error
+}
+
+// listParallel fetches the first page to know the number of pages, then fetches the others with a
+// pool of workers. At most options.Buffer pages are fetched but not yet consumed
+func listParallel[T any](ctx context.Context, params *ListParams, options *ParallelOptions, fetch func(context.Context, *ListParams) ([]T, PageInfo, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		opts := options.withDefaults()
+
+		firstPage := 0
+		if params != nil {
+			firstPage = params.Page
+		}
+		items, pageInfo, err := fetch(ctx, copyListParams(params, firstPage))
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+		if firstPage+1 >= pageInfo.TotalPages {
+			return
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+
+		// A token is taken for each dispatched page and given back once the page is consumed
+		tokens := make(chan struct{}, opts.Buffer)
+		jobs := make(chan int)
+		results := make(chan pageResult[T], opts.Buffer)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(jobs)
+			for page := firstPage + 1; page < pageInfo.TotalPages; page++ {
+				select {
+				case tokens <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				select {
+				case jobs <- page:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		for i := 0; i < opts.Workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for page := range jobs {
+					items, _, err := fetch(ctx, copyListParams(params, page))
+					// Never blocks, as there are at most opts.Buffer results waiting
+					results <- pageResult[T]{page: page, items: items, err: err}
+				}
+			}()
+		}
+
+		yieldPage := func(items []T) bool {
+			for _, item := range items {
+				if !yield(item, nil) {
+					return false
+				}
+			}
+			<-tokens
+			return true
+		}
+
+		pending := map[int][]T{}
+		next := firstPage + 1
+		for received := firstPage + 1; received < pageInfo.TotalPages; received++ {
+			var result pageResult[T]
+			select {
+			case result = <-results:
+			case <-ctx.Done():
+				yield(zero, ctx.Err())
+				return
+			}
+
+			if result.err != nil {
+				yield(zero, result.err)
+				return
+			}
+
+			if opts.Unordered {
+				if !yieldPage(result.items) {
+					return
+				}
+				continue
+			}
+
+			pending[result.page] = result.items
+			for items, ok := pending[next]; ok; items, ok = pending[next] {
+				delete(pending, next)
+				next++
+				if !yieldPage(items) {
+					return
+				}
+			}
+		}
+	}
+}
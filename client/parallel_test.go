@@ -0,0 +1,164 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// handleSlowPages serves totalPages pages of one element, the earlier pages being the slowest
+func handleSlowPages(t *testing.T, totalPages int, failPage int) (maxConcurrent func() int) {
+	var mu sync.Mutex
+	concurrent, max := 0, 0
+	mux.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		concurrent++
+		if concurrent > max {
+			max = concurrent
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			concurrent--
+			mu.Unlock()
+		}()
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page > 0 {
+			time.Sleep(time.Duration(totalPages-page) * time.Millisecond)
+		}
+		if page == failPage {
+			http.Error(w, `{"message":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"page":{"size":1,"totalElements":%d,"totalPages":%d,"number":%d},"elements":[{"id":"%d"}]}`,
+			totalPages, totalPages, page, page)
+	})
+
+	return func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return max
+	}
+}
+
+func TestCustomerListParallel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	maxConcurrent := handleSlowPages(t, 10, -1)
+
+	var IDs []string
+	for customer, err := range testClient.Customers.ListParallel(context.Background(), &ListParams{PageSize: 1}, &ParallelOptions{Workers: 3}) {
+		if err != nil {
+			t.Fatalf("Unexpected error. ListParallel: %v", err)
+		}
+		IDs = append(IDs, customer.ID)
+	}
+
+	expected := []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}
+	if diff := pretty.Compare(IDs, expected); diff != "" {
+		t.Errorf("ListParallel: invalid elements: (-got +expected)\n%s", diff)
+	}
+	if max := maxConcurrent(); max > 3 {
+		t.Errorf("Expected at most 3 concurrent requests, got %v", max)
+	}
+}
+
+func TestCustomerListParallelUnordered(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleSlowPages(t, 10, -1)
+
+	var IDs []string
+	for customer, err := range testClient.Customers.ListParallel(context.Background(), nil, &ParallelOptions{Workers: 5, Unordered: true}) {
+		if err != nil {
+			t.Fatalf("Unexpected error. ListParallel: %v", err)
+		}
+		IDs = append(IDs, customer.ID)
+	}
+
+	sort.Strings(IDs)
+	expected := []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}
+	if diff := pretty.Compare(IDs, expected); diff != "" {
+		t.Errorf("ListParallel: invalid elements: (-got +expected)\n%s", diff)
+	}
+}
+
+func TestCustomerListParallelError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleSlowPages(t, 10, 4)
+
+	var IDs []string
+	var lastErr error
+	for customer, err := range testClient.Customers.ListParallel(context.Background(), nil, &ParallelOptions{Workers: 2}) {
+		if err != nil {
+			lastErr = err
+			break
+		}
+		IDs = append(IDs, customer.ID)
+	}
+
+	if !IsServerError(lastErr) {
+		t.Errorf("Expected the error of page 4, got %v", lastErr)
+	}
+	for _, ID := range IDs {
+		if page, _ := strconv.Atoi(ID); page >= 4 {
+			t.Errorf("Expected no elements after the failed page, got %v", IDs)
+		}
+	}
+}
+
+func TestCustomerListParallelBreak(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleSlowPages(t, 10, -1)
+
+	count := 0
+	for _, err := range testClient.Customers.ListParallel(context.Background(), nil, &ParallelOptions{Workers: 2, Buffer: 2}) {
+		if err != nil {
+			t.Fatalf("Unexpected error. ListParallel: %v", err)
+		}
+		count++
+		if count == 3 {
+			break
+		}
+	}
+
+	if count != 3 {
+		t.Errorf("Expected 3 elements, got %v", count)
+	}
+}
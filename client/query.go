@@ -0,0 +1,280 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Operator is a comparison operator of the ContactHub query language
+type Operator string
+
+// Operators supported by atomic conditions
+const (
+	OpEquals    Operator = "EQUALS"
+	OpNotEquals Operator = "NOT_EQUALS"
+	OpGT        Operator = "GT"
+	OpGTE       Operator = "GTE"
+	OpLT        Operator = "LT"
+	OpLTE       Operator = "LTE"
+	OpBetween   Operator = "BETWEEN"
+	OpIn        Operator = "IN"
+	OpNotIn     Operator = "NOT_IN"
+	OpIsNull    Operator = "IS_NULL"
+	OpIsNotNull Operator = "IS_NOT_NULL"
+)
+
+// SortDirection is the direction of the customers sorting
+type SortDirection string
+
+// Sort directions
+const (
+	Ascending  SortDirection = "asc"
+	Descending SortDirection = "desc"
+)
+
+// Condition is a node of a customer query: either an atomic condition on an attribute,
+// or a composite one combining other conditions. Conditions are built via Field, And and Or
+type Condition struct {
+	Type        string      `json:"type"`
+	Attribute   string      `json:"attribute,omitempty"`
+	Operator    Operator    `json:"operator,omitempty"`
+	Value       interface{} `json:"value,omitempty"`
+	Conjunction string      `json:"conjunction,omitempty"`
+	Conditions  []Condition `json:"conditions,omitempty"`
+}
+
+// FieldRef references a customer attribute in a Condition
+type FieldRef string
+
+// Field references a customer attribute by its full path, e.g. "base.contacts.email"
+func Field(path string) FieldRef {
+	return FieldRef(path)
+}
+
+// BaseField references a base property, e.g. BaseField("contacts.email")
+func BaseField(path string) FieldRef {
+	return FieldRef("base." + path)
+}
+
+// ExtendedField references an extended property, e.g. ExtendedField("loyalty.level")
+func ExtendedField(path string) FieldRef {
+	return FieldRef("extended." + path)
+}
+
+// AutoTags references the automatic tags of the customer
+func AutoTags() FieldRef {
+	return FieldRef("tags.auto")
+}
+
+// ManualTags references the manual tags of the customer
+func ManualTags() FieldRef {
+	return FieldRef("tags.manual")
+}
+
+// RegisteredAt references the registration date of the customer
+func RegisteredAt() FieldRef {
+	return FieldRef("registeredAt")
+}
+
+// UpdatedAt references the last update date of the customer
+func UpdatedAt() FieldRef {
+	return FieldRef("updatedAt")
+}
+
+func (f FieldRef) atomic(operator Operator, value interface{}) Condition {
+	return Condition{Type: "atomic", Attribute: string(f), Operator: operator, Value: value}
+}
+
+// Equals matches the customers whose attribute is equal to value
+func (f FieldRef) Equals(value interface{}) Condition {
+	return f.atomic(OpEquals, value)
+}
+
+// NotEquals matches the customers whose attribute is not equal to value
+func (f FieldRef) NotEquals(value interface{}) Condition {
+	return f.atomic(OpNotEquals, value)
+}
+
+// GT matches the customers whose attribute is greater than value
+func (f FieldRef) GT(value interface{}) Condition {
+	return f.atomic(OpGT, value)
+}
+
+// GTE matches the customers whose attribute is greater than or equal to value
+func (f FieldRef) GTE(value interface{}) Condition {
+	return f.atomic(OpGTE, value)
+}
+
+// LT matches the customers whose attribute is less than value
+func (f FieldRef) LT(value interface{}) Condition {
+	return f.atomic(OpLT, value)
+}
+
+// LTE matches the customers whose attribute is less than or equal to value
+func (f FieldRef) LTE(value interface{}) Condition {
+	return f.atomic(OpLTE, value)
+}
+
+// In matches the customers whose attribute is one of values. For tags, it matches any of the tags
+func (f FieldRef) In(values ...interface{}) Condition {
+	return f.atomic(OpIn, values)
+}
+
+// NotIn matches the customers whose attribute is none of values
+func (f FieldRef) NotIn(values ...interface{}) Condition {
+	return f.atomic(OpNotIn, values)
+}
+
+// IsNull matches the customers without the attribute
+func (f FieldRef) IsNull() Condition {
+	return f.atomic(OpIsNull, nil)
+}
+
+// IsNotNull matches the customers with the attribute
+func (f FieldRef) IsNotNull() Condition {
+	return f.atomic(OpIsNotNull, nil)
+}
+
+// Between matches the dates between from and to, both included. A zero from or to leaves the range open,
+// so that a range open at both ends matches all the customers with the attribute, as IsNotNull
+func (f FieldRef) Between(from, to time.Time) Condition {
+	switch {
+	case from.IsZero() && to.IsZero():
+		return f.IsNotNull()
+	case from.IsZero():
+		return f.atomic(OpLTE, to.Format(defaultDateFormat))
+	case to.IsZero():
+		return f.atomic(OpGTE, from.Format(defaultDateFormat))
+	}
+	return f.atomic(OpBetween, []string{from.Format(defaultDateFormat), to.Format(defaultDateFormat)})
+}
+
+// And matches the customers satisfying all the conditions
+func And(conditions ...Condition) Condition {
+	return Condition{Type: "composite", Conjunction: "and", Conditions: conditions}
+}
+
+// Or matches the customers satisfying any of the conditions
+func Or(conditions ...Condition) Condition {
+	return Condition{Type: "composite", Conjunction: "or", Conditions: conditions}
+}
+
+func (c *Condition) validate() error {
+	switch c.Type {
+	case "atomic":
+		if c.Attribute == "" {
+			return errors.New("missing attribute in query condition")
+		}
+		if c.Operator == "" {
+			return fmt.Errorf("missing operator in query condition on %s", c.Attribute)
+		}
+	case "composite":
+		if len(c.Conditions) == 0 {
+			return fmt.Errorf("empty %s query condition", c.Conjunction)
+		}
+		for i := range c.Conditions {
+			if err := c.Conditions[i].validate(); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("invalid query condition type %q", c.Type)
+	}
+	return nil
+}
+
+// CustomerQuery contains the filters, the sorting and the projection of a customers listing
+type CustomerQuery struct {
+	// ExternalID matches the customers with the given externalId
+	ExternalID string
+	// Where is encoded in the ContactHub query language
+	Where *Condition
+	// Fields restricts the returned properties, e.g. "base.firstName"
+	Fields []string
+	// Sort is the attribute used for sorting, e.g. "base.lastName"
+	Sort      string
+	Direction SortDirection
+}
+
+type customerQueryRequest struct {
+	Name  string             `json:"name"`
+	Query simpleQueryRequest `json:"query"`
+}
+
+type simpleQueryRequest struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Are  struct {
+		Condition *Condition `json:"condition"`
+	} `json:"are"`
+}
+
+// QueryParams encodes the query into the params expected by the List endpoint
+func (q *CustomerQuery) QueryParams() (QueryParams, error) {
+	params := QueryParams{}
+	if q.ExternalID != "" {
+		params["externalId"] = q.ExternalID
+	}
+	if q.Where != nil {
+		if err := q.Where.validate(); err != nil {
+			return nil, err
+		}
+		request := customerQueryRequest{Name: "query", Query: simpleQueryRequest{Type: "simple", Name: "query"}}
+		request.Query.Are.Condition = q.Where
+		query, err := json.Marshal(request)
+		if err != nil {
+			return nil, err
+		}
+		params["query"] = string(query)
+	}
+	if len(q.Fields) > 0 {
+		params["fields"] = strings.Join(q.Fields, ",")
+	}
+	if q.Sort != "" {
+		params["sort"] = q.Sort
+		if q.Direction != "" {
+			params["direction"] = string(q.Direction)
+		}
+	}
+	return params, nil
+}
+
+// Apply adds the query to the QueryParams of params, overriding the existing values
+func (q *CustomerQuery) Apply(params *ListParams) error {
+	queryParams, err := q.QueryParams()
+	if err != nil {
+		return err
+	}
+	if params.QueryParams == nil {
+		params.QueryParams = QueryParams{}
+	}
+	for k, v := range queryParams {
+		params.QueryParams[k] = v
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestCustomerQueryParams(t *testing.T) {
+	from := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2017, 6, 30, 23, 59, 59, 0, time.UTC)
+	where := And(
+		BaseField("contacts.email").Equals("john@example.com"),
+		Or(ExtendedField("loyalty.level").GTE(3), ManualTags().In("vip", "premium")),
+		RegisteredAt().Between(from, to),
+		UpdatedAt().Between(from, time.Time{}),
+		BaseField("dob").IsNull(),
+	)
+	query := &CustomerQuery{
+		ExternalID: "my-external-id",
+		Where:      &where,
+		Fields:     []string{"base.firstName", "base.lastName"},
+		Sort:       "base.lastName",
+		Direction:  Descending,
+	}
+
+	params, err := query.QueryParams()
+	if err != nil {
+		t.Fatalf("Unexpected error. QueryParams: %v", err)
+	}
+
+	var encoded interface{}
+	if err := json.Unmarshal([]byte(params["query"]), &encoded); err != nil {
+		t.Fatalf("Unexpected error. Unmarshal: %v", err)
+	}
+	var expected interface{}
+	json.Unmarshal([]byte(`{
+	"name": "query",
+	"query": {
+		"type": "simple",
+		"name": "query",
+		"are": {
+			"condition": {
+				"type": "composite",
+				"conjunction": "and",
+				"conditions": [
+					{"type": "atomic", "attribute": "base.contacts.email", "operator": "EQUALS", "value": "john@example.com"},
+					{"type": "composite", "conjunction": "or", "conditions": [
+						{"type": "atomic", "attribute": "extended.loyalty.level", "operator": "GTE", "value": 3},
+						{"type": "atomic", "attribute": "tags.manual", "operator": "IN", "value": ["vip", "premium"]}
+					]},
+					{"type": "atomic", "attribute": "registeredAt", "operator": "BETWEEN", "value": ["2017-01-01T00:00:00+0000", "2017-06-30T23:59:59+0000"]},
+					{"type": "atomic", "attribute": "updatedAt", "operator": "GTE", "value": "2017-01-01T00:00:00+0000"},
+					{"type": "atomic", "attribute": "base.dob", "operator": "IS_NULL"}
+				]
+			}
+		}
+	}
+}`), &expected)
+	if diff := pretty.Compare(encoded, expected); diff != "" {
+		t.Errorf("QueryParams: invalid query: (-got +expected)\n%s", diff)
+	}
+
+	delete(params, "query")
+	expectedParams := QueryParams{
+		"externalId": "my-external-id",
+		"fields":     "base.firstName,base.lastName",
+		"sort":       "base.lastName",
+		"direction":  "desc",
+	}
+	if diff := pretty.Compare(params, expectedParams); diff != "" {
+		t.Errorf("QueryParams: invalid params: (-got +expected)\n%s", diff)
+	}
+}
+
+func TestCustomerQueryInvalid(t *testing.T) {
+	cases := []Condition{
+		And(),
+		Or(BaseField("firstName").Equals("John"), And()),
+		Field("").Equals("John"),
+		{},
+	}
+
+	for _, where := range cases {
+		query := &CustomerQuery{Where: &where}
+		if _, err := query.QueryParams(); err == nil {
+			t.Errorf("Expected error for %+v", where)
+		}
+	}
+}
+
+func TestConditionOpenRange(t *testing.T) {
+	where := RegisteredAt().Between(time.Time{}, time.Time{})
+	if diff := pretty.Compare(where, RegisteredAt().IsNotNull()); diff != "" {
+		t.Errorf("Between: invalid condition: (-got +expected)\n%s", diff)
+	}
+	query := &CustomerQuery{Where: &where}
+	if _, err := query.QueryParams(); err != nil {
+		t.Errorf("Unexpected error. QueryParams: %v", err)
+	}
+}
+
+func TestCustomerListWithQuery(t *testing.T) {
+	setup()
+	defer teardown()
+
+	where := BaseField("firstName").Equals("John")
+	query := &CustomerQuery{Where: &where, Fields: []string{"base.firstName"}}
+	params := &ListParams{QueryParams: QueryParams{"nodeId": "othernodeid"}}
+	if err := query.Apply(params); err != nil {
+		t.Fatalf("Unexpected error. Apply: %v", err)
+	}
+
+	mux.HandleFunc("/customers", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQueryStringValue(t, r, "nodeId", "othernodeid")
+		testQueryStringValue(t, r, "fields", "base.firstName")
+		testQueryStringValue(t, r, "query", `{"name":"query","query":{"type":"simple","name":"query","are":{"condition":{"type":"atomic","attribute":"base.firstName","operator":"EQUALS","value":"John"}}}}`)
+		fmt.Fprint(w, `{"page":{"size":20,"totalElements":0,"totalPages":0,"number":0},"elements":[]}`)
+	})
+
+	if _, _, err := testClient.Customers.List(params); err != nil {
+		t.Errorf("Unexpected error. Customers.List: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when the client rate limit is reached and RateLimit.FailFast is set.
+// It is matched by 429 ErrorResponses as well
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimit configures the client-side token bucket shared by all the services of a Client
+type RateLimit struct {
+	// RequestsPerSecond is the rate at which the bucket is refilled
+	RequestsPerSecond float64
+	// Burst is the size of the bucket. Defaults to 1
+	Burst int
+	// FailFast makes requests fail with ErrRateLimited instead of waiting for a token
+	FailFast bool
+}
+
+// rateLimiter is a token bucket which also backs off when the API reports throttling
+type rateLimiter struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	failFast    bool
+}
+
+func newRateLimiter(config *RateLimit) (*rateLimiter, error) {
+	if config.RequestsPerSecond <= 0 {
+		return nil, errors.New("RateLimit.RequestsPerSecond must be positive")
+	}
+	if config.Burst < 1 {
+		config.Burst = 1
+	}
+	return &rateLimiter{
+		rate:     config.RequestsPerSecond,
+		burst:    float64(config.Burst),
+		tokens:   float64(config.Burst),
+		last:     time.Now(),
+		failFast: config.FailFast,
+	}, nil
+}
+
+// wait takes a token from the bucket, blocking until one is available or ctx is done
+// It returns how long the caller has been blocked
+func (l *rateLimiter) wait(ctx context.Context) (time.Duration, error) {
+	var waited time.Duration
+	for {
+		delay := l.take()
+		if delay == 0 {
+			return waited, nil
+		}
+		if l.failFast {
+			return waited, ErrRateLimited
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return waited, err
+		}
+		waited += delay
+	}
+}
+
+// take removes a token from the bucket, or returns how long to wait for the next one
+func (l *rateLimiter) take() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// observe adapts the bucket to the throttling info returned by the API
+func (l *rateLimiter) observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	var pause time.Duration
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if wait, ok := retryAfter(resp); ok {
+			pause = wait
+		} else {
+			pause = time.Duration(float64(time.Second) / l.rate)
+		}
+	} else if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if wait, ok := rateLimitReset(resp); ok {
+			pause = wait
+		}
+	}
+	if pause <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = 0
+	if until := time.Now().Add(pause); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// rateLimitReset parses the X-RateLimit-Reset header, either in seconds or as a unix timestamp
+func rateLimitReset(resp *http.Response) (time.Duration, bool) {
+	value, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+	// Values this large can only be timestamps
+	if value > 1000000000 {
+		return time.Until(time.Unix(value, 0)), true
+	}
+	return time.Duration(value) * time.Second, true
+}
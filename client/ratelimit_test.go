@@ -0,0 +1,131 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter, err := newRateLimiter(&RateLimit{RequestsPerSecond: 50, Burst: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error. newRateLimiter: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if waited, err := limiter.wait(context.Background()); err != nil || waited != 0 {
+			t.Errorf("Expected token %v to be available, waited %v (%v)", i, waited, err)
+		}
+	}
+
+	start := time.Now()
+	if _, err := limiter.wait(context.Background()); err != nil {
+		t.Fatalf("Unexpected error. wait: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("Expected to wait for a token refill, waited %v", elapsed)
+	}
+}
+
+func TestRateLimiterFailFast(t *testing.T) {
+	limiter, _ := newRateLimiter(&RateLimit{RequestsPerSecond: 1, FailFast: true})
+
+	if _, err := limiter.wait(context.Background()); err != nil {
+		t.Fatalf("Unexpected error. wait: %v", err)
+	}
+	if _, err := limiter.wait(context.Background()); err != ErrRateLimited {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestRateLimiterContext(t *testing.T) {
+	limiter, _ := newRateLimiter(&RateLimit{RequestsPerSecond: 0.1})
+	limiter.wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRateLimiterObserve(t *testing.T) {
+	limiter, _ := newRateLimiter(&RateLimit{RequestsPerSecond: 1000, Burst: 10})
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "1")
+	limiter.observe(resp)
+	if delay := limiter.take(); delay < 900*time.Millisecond {
+		t.Errorf("Expected the limiter to pause after a 429, got %v", delay)
+	}
+
+	limiter, _ = newRateLimiter(&RateLimit{RequestsPerSecond: 1000, Burst: 10})
+	resp = &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set("X-RateLimit-Remaining", "0")
+	resp.Header.Set("X-RateLimit-Reset", "2")
+	limiter.observe(resp)
+	if delay := limiter.take(); delay < 1900*time.Millisecond {
+		t.Errorf("Expected the limiter to pause until the quota reset, got %v", delay)
+	}
+}
+
+func TestNewWithInvalidRateLimit(t *testing.T) {
+	_, err := New(&Config{APIkey: "key", WorkspaceID: "ID", RateLimit: &RateLimit{}})
+	if err == nil {
+		t.Error("Expected error.")
+	}
+}
+
+func TestRateLimitedClient(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "{}")
+	})
+
+	testClient.limiter, _ = newRateLimiter(&RateLimit{RequestsPerSecond: 100, Burst: 1})
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := testClient.NewRequest(http.MethodGet, "/", nil)
+			if _, err := testClient.Do(req, nil); err != nil {
+				t.Errorf("Unexpected error. Do: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("Expected the requests to be throttled, took %v", elapsed)
+	}
+}
@@ -0,0 +1,210 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is the default number of attempts, including the first one
+	DefaultMaxAttempts = 3
+	// DefaultBaseBackoff is the default wait before the first retry
+	DefaultBaseBackoff = 200 * time.Millisecond
+	// DefaultMaxBackoff is the default upper bound of the exponential backoff
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// RetryPolicy configures the automatic retries performed by Client.Do
+// Zero values are replaced with the defaults
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// BaseBackoff is the wait before the first retry, doubled on every following retry
+	BaseBackoff time.Duration
+	// MaxBackoff caps the exponential backoff. It does not apply to Retry-After headers
+	MaxBackoff time.Duration
+	// Jitter is the fraction (0 to 1) of the backoff that is randomized
+	Jitter float64
+	// RetryableStatusCodes are the response status codes that trigger a retry
+	RetryableStatusCodes []int
+	// RetryableMethods are the HTTP methods that can be retried
+	RetryableMethods []string
+	// AllowNonIdempotent allows retrying POST and PATCH requests as well
+	AllowNonIdempotent bool
+	// OnRetry, if set, is called before waiting for each retry
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent contains info about a failed attempt which is going to be retried
+type RetryEvent struct {
+	// Attempt is the number of the failed attempt, starting from 1
+	Attempt  int
+	Request  *http.Request
+	Response *http.Response
+	Err      error
+	// Wait is how long the client is going to wait before the next attempt
+	Wait time.Duration
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with the default values
+func DefaultRetryPolicy() *RetryPolicy {
+	policy := &RetryPolicy{}
+	policy.setDefaults()
+	return policy
+}
+
+func (p *RetryPolicy) setDefaults() {
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+	if p.BaseBackoff <= 0 {
+		p.BaseBackoff = DefaultBaseBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxBackoff
+	}
+	if p.Jitter < 0 {
+		p.Jitter = 0
+	} else if p.Jitter > 1 {
+		p.Jitter = 1
+	}
+	if p.RetryableStatusCodes == nil {
+		p.RetryableStatusCodes = []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		}
+	}
+	if p.RetryableMethods == nil {
+		p.RetryableMethods = []string{
+			http.MethodGet,
+			http.MethodHead,
+			http.MethodOptions,
+			http.MethodPut,
+			http.MethodDelete,
+		}
+	}
+}
+
+// canRetry checks whether the method of req may be retried at all
+func (p *RetryPolicy) canRetry(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if p.AllowNonIdempotent && (req.Method == http.MethodPost || req.Method == http.MethodPatch) {
+		return true
+	}
+	for _, method := range p.RetryableMethods {
+		if method == req.Method {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldRetry checks whether the outcome of an attempt is a transient failure
+func (p *RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		// Failing fast on the client rate limit must not turn into a retry loop
+		return err != ErrRateLimited
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if code == resp.StatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// transient checks whether the error returned by a service method is a transient failure, i.e. a transport
+// error or a retryable status code, and returns the response of the failed call, if any.
+// Only the status code is checked, as the error pages of gateways and proxies are usually not JSON
+func (p *RetryPolicy) transient(err error) (*http.Response, bool) {
+	var errorResponse *ErrorResponse
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &errorResponse):
+		return errorResponse.Response, p.shouldRetry(errorResponse.Response, nil)
+	case errors.As(err, &urlErr):
+		return nil, true
+	}
+	return nil, false
+}
+
+// backoff computes the wait before the retry following the given attempt
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if wait, ok := retryAfter(resp); ok {
+		return wait
+	}
+
+	wait := float64(p.BaseBackoff) * math.Pow(2, float64(attempt-1))
+	if wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	wait -= wait * p.Jitter * rand.Float64()
+	return time.Duration(wait)
+}
+
+// retryAfter parses the Retry-After header, either in seconds or as an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// sleep waits for the given duration, unless ctx is done first
+func sleep(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
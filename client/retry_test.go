@@ -0,0 +1,221 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseBackoff = time.Millisecond
+	policy.MaxBackoff = 2 * time.Millisecond
+	return policy
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			http.Error(w, `{"message":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"id":"ok"}`)
+	})
+
+	var events []RetryEvent
+	testClient.Config.RetryPolicy = testRetryPolicy()
+	testClient.Config.RetryPolicy.OnRetry = func(e RetryEvent) {
+		events = append(events, e)
+	}
+
+	req, _ := testClient.NewRequest(http.MethodGet, "/", nil)
+	object := make(map[string]interface{})
+	_, err := testClient.Do(req, &object)
+	if err != nil {
+		t.Fatalf("Unexpected error. Do: %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %v", calls)
+	}
+	if object["id"] != "ok" {
+		t.Errorf("Expected the last response to be decoded, got %v", object)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 retry events, got %v", len(events))
+	}
+	if events[0].Attempt != 1 || events[0].Response.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Unexpected retry event: %+v", events[0])
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, `{"message":"bad gateway"}`, http.StatusBadGateway)
+	})
+
+	testClient.Config.RetryPolicy = testRetryPolicy()
+
+	req, _ := testClient.NewRequest(http.MethodGet, "/", nil)
+	_, err := testClient.Do(req, nil)
+
+	if _, ok := err.(*ErrorResponse); !ok {
+		t.Errorf("Expected *ErrorResponse, got %v", err)
+	}
+	if calls != DefaultMaxAttempts {
+		t.Errorf("Expected %v attempts, got %v", DefaultMaxAttempts, calls)
+	}
+}
+
+func TestRetryNotRetryableStatus(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+	})
+
+	testClient.Config.RetryPolicy = testRetryPolicy()
+
+	req, _ := testClient.NewRequest(http.MethodGet, "/", nil)
+	testClient.Do(req, nil)
+
+	if calls != 1 {
+		t.Errorf("Expected 1 attempt, got %v", calls)
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		if trimmedBody := strings.TrimSpace(string(body)); trimmedBody != `{"key":"value"}` {
+			t.Errorf("Invalid body on attempt %v: %v", calls, trimmedBody)
+		}
+		http.Error(w, `{"message":"unavailable"}`, http.StatusServiceUnavailable)
+	})
+
+	testClient.Config.RetryPolicy = testRetryPolicy()
+
+	req, _ := testClient.NewRequest(http.MethodPost, "/", map[string]string{"key": "value"})
+	testClient.Do(req, nil)
+	if calls != 1 {
+		t.Errorf("Expected POST not to be retried, got %v attempts", calls)
+	}
+
+	calls = 0
+	testClient.Config.RetryPolicy.AllowNonIdempotent = true
+	req, _ = testClient.NewRequest(http.MethodPost, "/", map[string]string{"key": "value"})
+	testClient.Do(req, nil)
+	if calls != DefaultMaxAttempts {
+		t.Errorf("Expected POST to be retried, got %v attempts", calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, `{"message":"slow down"}`, http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	var wait time.Duration
+	testClient.Config.RetryPolicy = testRetryPolicy()
+	testClient.Config.RetryPolicy.BaseBackoff = time.Hour
+	testClient.Config.RetryPolicy.OnRetry = func(e RetryEvent) {
+		wait = e.Wait
+	}
+
+	req, _ := testClient.NewRequest(http.MethodGet, "/", nil)
+	if _, err := testClient.Do(req, nil); err != nil {
+		t.Fatalf("Unexpected error. Do: %v", err)
+	}
+	if calls != 2 || wait != 0 {
+		t.Errorf("Expected a single immediate retry, got %v attempts and a %v wait", calls, wait)
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	if _, ok := retryAfter(resp); ok {
+		t.Error("Expected no Retry-After")
+	}
+
+	resp.Header.Set("Retry-After", "120")
+	if wait, ok := retryAfter(resp); !ok || wait != 2*time.Minute {
+		t.Errorf("Expected 2m, got %v", wait)
+	}
+
+	resp.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	if wait, ok := retryAfter(resp); !ok || wait < 59*time.Minute || wait > time.Hour {
+		t.Errorf("Expected about 1h, got %v", wait)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	policy.setDefaults()
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, e := range expected {
+		if wait := policy.backoff(i+1, nil); wait != e {
+			t.Errorf("Attempt %v: expected %v, got %v", i+1, e, wait)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		if wait := policy.backoff(1, nil); wait < 50*time.Millisecond || wait > 100*time.Millisecond {
+			t.Errorf("Jittered backoff out of range: %v", wait)
+		}
+	}
+}
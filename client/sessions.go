@@ -23,7 +23,11 @@
 package client
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 )
 
@@ -41,6 +45,45 @@ type SessionResponse struct {
 	Value string `json:"value,required"`
 }
 
+// SessionNotFoundError is returned by FindByValue when the customer has no session with the value.
+// It matches ErrNotFound via errors.Is
+type SessionNotFoundError struct {
+	CustomerID string
+	Value      string
+}
+
+func (e *SessionNotFoundError) Error() string {
+	return fmt.Sprintf("no session %q found for customer %s", e.Value, e.CustomerID)
+}
+
+// Is matches ErrNotFound
+func (e *SessionNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// sessionListResponse accepts both the paginated response and the plain list of sessions
+type sessionListResponse struct {
+	PageInfo PageInfo
+	Sessions []SessionResponse
+}
+
+// UnmarshalJSON implements the Unmarshaler interface
+func (r *sessionListResponse) UnmarshalJSON(b []byte) error {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, &r.Sessions)
+	}
+
+	var page struct {
+		PageInfo PageInfo          `json:"page"`
+		Sessions []SessionResponse `json:"elements"`
+	}
+	if err := json.Unmarshal(b, &page); err != nil {
+		return err
+	}
+	r.PageInfo, r.Sessions = page.PageInfo, page.Sessions
+	return nil
+}
+
 // SessionService provides access to the Sessions API
 type SessionService struct {
 	client *Client
@@ -48,8 +91,13 @@ type SessionService struct {
 
 // Get returns an individual session of a customer
 func (s *SessionService) Get(customerID, ID string) (*SessionResponse, error) {
+	return s.GetWithContext(context.Background(), customerID, ID)
+}
+
+// GetWithContext is the context-aware version of Get
+func (s *SessionService) GetWithContext(ctx context.Context, customerID, ID string) (*SessionResponse, error) {
 	path := fmt.Sprintf(sessionBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -64,8 +112,13 @@ func (s *SessionService) Get(customerID, ID string) (*SessionResponse, error) {
 
 // Create creates a new Session for the Customer, returns the response
 func (s *SessionService) Create(customerID string, session *Session) (*SessionResponse, error) {
+	return s.CreateWithContext(context.Background(), customerID, session)
+}
+
+// CreateWithContext is the context-aware version of Create
+func (s *SessionService) CreateWithContext(ctx context.Context, customerID string, session *Session) (*SessionResponse, error) {
 	path := fmt.Sprintf(sessionBasePath, customerID)
-	req, err := s.client.NewRequest(http.MethodPost, path, session)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPost, path, session)
 	if err != nil {
 		return nil, err
 	}
@@ -80,26 +133,71 @@ func (s *SessionService) Create(customerID string, session *Session) (*SessionRe
 }
 
 // List gets a list of sessions assigned to the customer
-func (s *SessionService) List(customerID string) ([]SessionResponse, error) {
-	path := fmt.Sprintf(sessionBasePath, customerID)
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+// The PageInfo is empty if the API returns all the sessions at once, without pagination
+func (s *SessionService) List(customerID string, params *ListParams) ([]SessionResponse, PageInfo, error) {
+	return s.ListWithContext(context.Background(), customerID, params)
+}
+
+// ListWithContext is the context-aware version of List
+func (s *SessionService) ListWithContext(ctx context.Context, customerID string, params *ListParams) ([]SessionResponse, PageInfo, error) {
+	if params == nil {
+		params = &ListParams{}
+	}
+	params.preparePagination()
+	path := addQuery(fmt.Sprintf(sessionBasePath, customerID), params.QueryParams)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
-		return nil, err
+		return nil, PageInfo{}, err
 	}
 
-	sessions := new([]SessionResponse)
-	_, err = s.client.Do(req, sessions)
+	List := &sessionListResponse{}
+	_, err = s.client.Do(req, List)
 	if err != nil {
-		return nil, err
+		return nil, PageInfo{}, err
 	}
 
-	return *sessions, nil
+	return List.Sessions, List.PageInfo, nil
+}
+
+// Iterate returns an Iterator over all the sessions of a customer, starting from params.Page
+func (s *SessionService) Iterate(ctx context.Context, customerID string, params *ListParams) *Iterator[SessionResponse] {
+	return newIterator(ctx, params, func(ctx context.Context, params *ListParams) ([]SessionResponse, PageInfo, error) {
+		return s.ListWithContext(ctx, customerID, params)
+	})
+}
+
+// ListAll returns all the sessions of a customer as a range-over-func sequence
+func (s *SessionService) ListAll(ctx context.Context, customerID string, params *ListParams) iter.Seq2[SessionResponse, error] {
+	return s.Iterate(ctx, customerID, params).All()
+}
+
+// FindByValue returns the session of the customer with the given value
+func (s *SessionService) FindByValue(customerID, value string) (*SessionResponse, error) {
+	return s.FindByValueWithContext(context.Background(), customerID, value)
+}
+
+// FindByValueWithContext is the context-aware version of FindByValue
+func (s *SessionService) FindByValueWithContext(ctx context.Context, customerID, value string) (*SessionResponse, error) {
+	for session, err := range s.ListAll(ctx, customerID, &ListParams{PageSize: maxPageSize}) {
+		if err != nil {
+			return nil, err
+		}
+		if session.Value == value {
+			return &session, nil
+		}
+	}
+	return nil, &SessionNotFoundError{CustomerID: customerID, Value: value}
 }
 
 // Delete deletes a Session
 func (s *SessionService) Delete(customerID, ID string) error {
+	return s.DeleteWithContext(context.Background(), customerID, ID)
+}
+
+// DeleteWithContext is the context-aware version of Delete
+func (s *SessionService) DeleteWithContext(ctx context.Context, customerID, ID string) error {
 	path := fmt.Sprintf(sessionBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
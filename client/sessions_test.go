@@ -23,6 +23,8 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -110,7 +112,7 @@ func TestSessionList(t *testing.T) {
 		},
 	}
 
-	sessions, err := testClient.Sessions.List("my-customer-id")
+	sessions, pageInfo, err := testClient.Sessions.List("my-customer-id", &ListParams{})
 
 	if err != nil {
 		t.Errorf("Unexpected error. Sessions.Update: %v", err)
@@ -119,6 +121,68 @@ func TestSessionList(t *testing.T) {
 	if diff := pretty.Compare(sessions, expectedResponse); diff != "" {
 		t.Errorf("Sessions.List: invalid value for struct: (-got +expected)\n%s", diff)
 	}
+	if diff := pretty.Compare(pageInfo, PageInfo{}); diff != "" {
+		t.Errorf("Sessions.List: invalid page info: (-got +expected)\n%s", diff)
+	}
+}
+
+func TestSessionListPaginated(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handlePages(t, "/customers/my-customer-id/sessions", 3, -1)
+
+	sessions, pageInfo, err := testClient.Sessions.List("my-customer-id", &ListParams{Page: 1, PageSize: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error. Sessions.List: %v", err)
+	}
+	expectedPageInfo := PageInfo{Size: 2, TotalElements: 6, TotalPages: 3, Page: 1}
+	if diff := pretty.Compare(pageInfo, expectedPageInfo); diff != "" {
+		t.Errorf("Sessions.List: invalid page info: (-got +expected)\n%s", diff)
+	}
+	if len(sessions) != 2 || sessions[0].ID != "1-0" {
+		t.Errorf("Sessions.List: unexpected sessions %v", sessions)
+	}
+
+	var IDs []string
+	for session, err := range testClient.Sessions.ListAll(context.Background(), "my-customer-id", &ListParams{PageSize: 2}) {
+		if err != nil {
+			t.Fatalf("Unexpected error. Sessions.ListAll: %v", err)
+		}
+		IDs = append(IDs, session.ID)
+	}
+	if diff := pretty.Compare(IDs, []string{"0-0", "0-1", "1-0", "1-1", "2-0", "2-1"}); diff != "" {
+		t.Errorf("Sessions.ListAll: invalid sessions: (-got +expected)\n%s", diff)
+	}
+}
+
+func TestSessionFindByValue(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/my-customer-id/sessions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQueryStringValue(t, r, "size", "50")
+		if r.URL.Query().Get("page") == "0" {
+			fmt.Fprint(w, `{"page":{"size":50,"totalElements":51,"totalPages":2,"number":0},"elements":[{"id":"session-0","value":"value-0"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"page":{"size":50,"totalElements":51,"totalPages":2,"number":1},"elements":[{"id":"session-1","value":"value-1"}]}`)
+	})
+
+	session, err := testClient.Sessions.FindByValue("my-customer-id", "value-1")
+	if err != nil {
+		t.Fatalf("Unexpected error. Sessions.FindByValue: %v", err)
+	}
+	if diff := pretty.Compare(session, &SessionResponse{ID: "session-1", Value: "value-1"}); diff != "" {
+		t.Errorf("Sessions.FindByValue: invalid session: (-got +expected)\n%s", diff)
+	}
+
+	_, err = testClient.Sessions.FindByValue("my-customer-id", "missing")
+	var notFound *SessionNotFoundError
+	if !errors.As(err, &notFound) || !IsNotFound(err) || notFound.Value != "missing" {
+		t.Errorf("Expected SessionNotFoundError, got %v", err)
+	}
 }
 
 func TestSessionDelete(t *testing.T) {
@@ -23,6 +23,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -70,8 +71,13 @@ type SubscriptionService struct {
 
 // Get returns an individual subscription of a customer
 func (s *SubscriptionService) Get(customerID, ID string) (*SubscriptionResponse, error) {
+	return s.GetWithContext(context.Background(), customerID, ID)
+}
+
+// GetWithContext is the context-aware version of Get
+func (s *SubscriptionService) GetWithContext(ctx context.Context, customerID, ID string) (*SubscriptionResponse, error) {
 	path := fmt.Sprintf(subscriptionBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -86,8 +92,13 @@ func (s *SubscriptionService) Get(customerID, ID string) (*SubscriptionResponse,
 
 // Create creates a new Subscription for the Customer, returns the response
 func (s *SubscriptionService) Create(customerID string, subscription *Subscription) (*SubscriptionResponse, error) {
+	return s.CreateWithContext(context.Background(), customerID, subscription)
+}
+
+// CreateWithContext is the context-aware version of Create
+func (s *SubscriptionService) CreateWithContext(ctx context.Context, customerID string, subscription *Subscription) (*SubscriptionResponse, error) {
 	path := fmt.Sprintf(subscriptionBasePath, customerID)
-	req, err := s.client.NewRequest(http.MethodPost, path, subscription)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPost, path, subscription)
 	if err != nil {
 		return nil, err
 	}
@@ -103,8 +114,13 @@ func (s *SubscriptionService) Create(customerID string, subscription *Subscripti
 
 // Update updates a Subscription via a put operation
 func (s *SubscriptionService) Update(customerID, ID string, subscription *Subscription) (*SubscriptionResponse, error) {
+	return s.UpdateWithContext(context.Background(), customerID, ID, subscription)
+}
+
+// UpdateWithContext is the context-aware version of Update
+func (s *SubscriptionService) UpdateWithContext(ctx context.Context, customerID, ID string, subscription *Subscription) (*SubscriptionResponse, error) {
 	path := fmt.Sprintf(subscriptionBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodPut, path, subscription)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodPut, path, subscription)
 	if err != nil {
 		return nil, err
 	}
@@ -120,8 +136,13 @@ func (s *SubscriptionService) Update(customerID, ID string, subscription *Subscr
 
 // Delete deletes a Subscription
 func (s *SubscriptionService) Delete(customerID, ID string) error {
+	return s.DeleteWithContext(context.Background(), customerID, ID)
+}
+
+// DeleteWithContext is the context-aware version of Delete
+func (s *SubscriptionService) DeleteWithContext(ctx context.Context, customerID, ID string) error {
 	path := fmt.Sprintf(subscriptionBasePath, customerID) + "/" + ID
-	req, err := s.client.NewRequest(http.MethodDelete, path, nil)
+	req, err := s.client.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if err != nil {
 		return err
 	}
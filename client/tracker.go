@@ -0,0 +1,290 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+const (
+	// DefaultTrackerQueueSize is the default capacity of the EventTracker queue
+	DefaultTrackerQueueSize = 1000
+	// DefaultTrackerWorkers is the default number of EventTracker workers
+	DefaultTrackerWorkers = 2
+)
+
+var (
+	// ErrTrackerClosed is returned when tracking events on a closed EventTracker
+	ErrTrackerClosed = errors.New("event tracker closed")
+	// ErrQueueFull is reported to OnDrop when an event is dropped because the queue is full
+	ErrQueueFull = errors.New("event queue full")
+)
+
+// OverflowPolicy decides what happens when an event is tracked while the queue is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes Track wait for a free slot
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest drops the oldest queued event to make room for the new one
+	OverflowDropOldest
+	// OverflowDropNewest drops the new event
+	OverflowDropNewest
+)
+
+// TrackerConfig configures an EventTracker. Zero values are replaced with the defaults
+type TrackerConfig struct {
+	// QueueSize is the maximum number of events waiting to be sent
+	QueueSize int
+	// Workers is the number of events sent concurrently
+	Workers  int
+	Overflow OverflowPolicy
+	// RetryPolicy configures the retries of the failed events. Nil means DefaultRetryPolicy
+	RetryPolicy *RetryPolicy
+	// OnDrop, if set, is called for every event dropped before being sent, with the reason
+	OnDrop func(event *Event, err error)
+	// OnFailure, if set, is called for every event which could not be sent after all the retries
+	OnFailure func(event *Event, err error)
+}
+
+// EventTracker sends the events in the background, from a bounded queue.
+// It must be closed in order to send the queued events
+type EventTracker struct {
+	events *EventService
+	config TrackerConfig
+	queue  chan *Event
+
+	// ctx is cancelled when Close gives up on the queued events
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// mu guards closed and the registration of senders. It is never held while waiting for the queue:
+	// the blocked senders give up when closing is closed, then queue is closed once senders is zero
+	mu        sync.RWMutex
+	closed    bool
+	closing   chan struct{}
+	senders   sync.WaitGroup
+	closeOnce sync.Once
+
+	// pendingMu guards pending, the number of queued and in-flight events, and idle, closed when pending is zero
+	pendingMu sync.Mutex
+	pending   int
+	idle      chan struct{}
+
+	workers sync.WaitGroup
+}
+
+// NewTracker creates an EventTracker and starts its workers
+func (s *EventService) NewTracker(config *TrackerConfig) *EventTracker {
+	t := &EventTracker{events: s}
+	if config != nil {
+		t.config = *config
+	}
+	if t.config.QueueSize < 1 {
+		t.config.QueueSize = DefaultTrackerQueueSize
+	}
+	if t.config.Workers < 1 {
+		t.config.Workers = DefaultTrackerWorkers
+	}
+	if t.config.RetryPolicy == nil {
+		t.config.RetryPolicy = DefaultRetryPolicy()
+	} else {
+		policy := *t.config.RetryPolicy
+		policy.setDefaults()
+		t.config.RetryPolicy = &policy
+	}
+
+	t.queue = make(chan *Event, t.config.QueueSize)
+	t.closing = make(chan struct{})
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+	t.idle = make(chan struct{})
+	close(t.idle)
+
+	t.workers.Add(t.config.Workers)
+	for i := 0; i < t.config.Workers; i++ {
+		go t.work()
+	}
+	return t
+}
+
+// Track queues the event to be sent in the background.
+// When the queue is full, it blocks or drops an event according to the OverflowPolicy
+func (t *EventTracker) Track(event *Event) error {
+	return t.TrackWithContext(context.Background(), event)
+}
+
+// TrackWithContext is the context-aware version of Track. ctx only bounds the wait for a free slot
+func (t *EventTracker) TrackWithContext(ctx context.Context, event *Event) error {
+	t.mu.RLock()
+	if t.closed {
+		t.mu.RUnlock()
+		return ErrTrackerClosed
+	}
+	t.senders.Add(1)
+	t.mu.RUnlock()
+	defer t.senders.Done()
+
+	t.add()
+	for {
+		select {
+		case t.queue <- event:
+			return nil
+		default:
+		}
+
+		switch t.config.Overflow {
+		case OverflowDropNewest:
+			t.drop(event, ErrQueueFull)
+			return nil
+		case OverflowDropOldest:
+			select {
+			case oldest := <-t.queue:
+				t.drop(oldest, ErrQueueFull)
+			default:
+			}
+		default:
+			select {
+			case t.queue <- event:
+				return nil
+			case <-ctx.Done():
+				t.done()
+				return ctx.Err()
+			case <-t.closing:
+				t.done()
+				return ErrTrackerClosed
+			}
+		}
+	}
+}
+
+// Flush waits until all the events tracked so far have been sent or dropped, or ctx is done
+func (t *EventTracker) Flush(ctx context.Context) error {
+	t.pendingMu.Lock()
+	idle := t.idle
+	t.pendingMu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting events and waits until the queued ones have been sent.
+// The calls to Track waiting for a free slot fail with ErrTrackerClosed.
+// If ctx is done first, the remaining events are dropped with ErrTrackerClosed and ctx.Err() is returned
+func (t *EventTracker) Close(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		t.closeOnce.Do(func() {
+			t.mu.Lock()
+			t.closed = true
+			close(t.closing)
+			t.mu.Unlock()
+			// No event can be sent to the queue anymore
+			t.senders.Wait()
+			close(t.queue)
+		})
+		t.workers.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.cancel()
+		return nil
+	case <-ctx.Done():
+		t.cancel()
+		<-stopped
+		return ctx.Err()
+	}
+}
+
+func (t *EventTracker) work() {
+	defer t.workers.Done()
+	for event := range t.queue {
+		if t.ctx.Err() != nil {
+			t.drop(event, ErrTrackerClosed)
+			continue
+		}
+		if err := t.send(event); err != nil {
+			if t.ctx.Err() != nil {
+				t.drop(event, ErrTrackerClosed)
+				continue
+			}
+			if t.config.OnFailure != nil {
+				t.config.OnFailure(event, err)
+			}
+		}
+		t.done()
+	}
+}
+
+// send creates the event, retrying the transient failures
+func (t *EventTracker) send(event *Event) error {
+	policy := t.config.RetryPolicy
+	for attempt := 1; ; attempt++ {
+		_, err := t.events.CreateWithContext(t.ctx, event)
+		if err == nil {
+			return nil
+		}
+
+		// Only transport errors and the retryable status codes are retried, e.g. invalid events are not
+		resp, retry := policy.transient(err)
+		if !retry || attempt >= policy.MaxAttempts || t.ctx.Err() != nil {
+			return err
+		}
+		if sleep(t.ctx, policy.backoff(attempt, resp)) != nil {
+			return err
+		}
+	}
+}
+
+// drop reports a dropped event
+func (t *EventTracker) drop(event *Event, err error) {
+	if t.config.OnDrop != nil {
+		t.config.OnDrop(event, err)
+	}
+	t.done()
+}
+
+func (t *EventTracker) add() {
+	t.pendingMu.Lock()
+	defer t.pendingMu.Unlock()
+	if t.pending == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.pending++
+}
+
+func (t *EventTracker) done() {
+	t.pendingMu.Lock()
+	defer t.pendingMu.Unlock()
+	t.pending--
+	if t.pending == 0 {
+		close(t.idle)
+	}
+}
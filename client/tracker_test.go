@@ -0,0 +1,323 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contactlab/contacthub-sdk-go/enums"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// eventRecorder records the events received by the test server, by their "n" property
+type eventRecorder struct {
+	mu       sync.Mutex
+	received []int
+	attempts map[int]int
+}
+
+func (r *eventRecorder) sorted() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	received := append([]int{}, r.received...)
+	sort.Ints(received)
+	return received
+}
+
+// handleTrackedEvents serves the events endpoint, replying with the status returned by status
+func handleTrackedEvents(t *testing.T, status func(n, attempt int) int, block <-chan struct{}, started chan<- int) *eventRecorder {
+	recorder := &eventRecorder{attempts: map[int]int{}}
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		event := new(Event)
+		json.NewDecoder(r.Body).Decode(event)
+		n := int(event.Properties["n"].(float64))
+
+		if started != nil {
+			started <- n
+		}
+		if block != nil {
+			select {
+			case <-block:
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		recorder.mu.Lock()
+		recorder.attempts[n]++
+		code := status(n, recorder.attempts[n])
+		if code == http.StatusOK {
+			recorder.received = append(recorder.received, n)
+		}
+		recorder.mu.Unlock()
+
+		w.WriteHeader(code)
+		w.Write([]byte(`{}`))
+	})
+	return recorder
+}
+
+func trackedEvent(n int) *Event {
+	return &Event{Type: enums.GenericActiveEvent, Context: enums.Other, Properties: map[string]interface{}{"n": n}}
+}
+
+func statusOK(n, attempt int) int {
+	return http.StatusOK
+}
+
+func TestEventTracker(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// Every event fails once before succeeding
+	recorder := handleTrackedEvents(t, func(n, attempt int) int {
+		if attempt == 1 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}, nil, nil)
+
+	var failures int
+	tracker := testClient.Events.NewTracker(&TrackerConfig{
+		Workers:     3,
+		RetryPolicy: testRetryPolicy(),
+		OnFailure:   func(*Event, error) { failures++ },
+	})
+	for n := 0; n < 10; n++ {
+		if err := tracker.Track(trackedEvent(n)); err != nil {
+			t.Fatalf("Unexpected error. Track: %v", err)
+		}
+	}
+
+	if err := tracker.Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error. Flush: %v", err)
+	}
+	if diff := pretty.Compare(recorder.sorted(), []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}); diff != "" {
+		t.Errorf("Flush: invalid events: (-got +expected)\n%s", diff)
+	}
+
+	if err := tracker.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected error. Close: %v", err)
+	}
+	if failures != 0 {
+		t.Errorf("Expected no failures, got %v", failures)
+	}
+	if err := tracker.Track(trackedEvent(10)); err != ErrTrackerClosed {
+		t.Errorf("Expected ErrTrackerClosed, got %v", err)
+	}
+}
+
+func TestEventTrackerFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	recorder := handleTrackedEvents(t, func(n, attempt int) int { return http.StatusBadRequest }, nil, nil)
+
+	var mu sync.Mutex
+	var failed []int
+	tracker := testClient.Events.NewTracker(&TrackerConfig{
+		RetryPolicy: testRetryPolicy(),
+		OnFailure: func(event *Event, err error) {
+			if !IsValidation(err) {
+				t.Errorf("Expected validation error, got %v", err)
+			}
+			mu.Lock()
+			failed = append(failed, event.Properties["n"].(int))
+			mu.Unlock()
+		},
+	})
+	tracker.Track(trackedEvent(0))
+	tracker.Track(trackedEvent(1))
+	if err := tracker.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected error. Close: %v", err)
+	}
+
+	sort.Ints(failed)
+	if diff := pretty.Compare(failed, []int{0, 1}); diff != "" {
+		t.Errorf("OnFailure: invalid events: (-got +expected)\n%s", diff)
+	}
+	if recorder.attempts[0] != 1 || recorder.attempts[1] != 1 {
+		t.Errorf("Expected no retries of non transient failures, got %v", recorder.attempts)
+	}
+}
+
+func TestEventTrackerOverflow(t *testing.T) {
+	cases := []struct {
+		overflow OverflowPolicy
+		dropped  int
+		sent     []int
+	}{
+		{OverflowDropNewest, 3, []int{0, 1, 2}},
+		{OverflowDropOldest, 1, []int{0, 2, 3}},
+	}
+
+	for _, c := range cases {
+		setup()
+		block, started := make(chan struct{}), make(chan int, 10)
+		recorder := handleTrackedEvents(t, statusOK, block, started)
+
+		var dropped []int
+		tracker := testClient.Events.NewTracker(&TrackerConfig{
+			QueueSize: 2,
+			Workers:   1,
+			Overflow:  c.overflow,
+			OnDrop: func(event *Event, err error) {
+				if err != ErrQueueFull {
+					t.Errorf("Expected ErrQueueFull, got %v", err)
+				}
+				dropped = append(dropped, event.Properties["n"].(int))
+			},
+		})
+
+		// The worker blocks on the first event, so the following ones fill the queue
+		tracker.Track(trackedEvent(0))
+		<-started
+		for n := 1; n < 4; n++ {
+			tracker.Track(trackedEvent(n))
+		}
+		close(block)
+
+		if err := tracker.Close(context.Background()); err != nil {
+			t.Fatalf("Unexpected error. Close: %v", err)
+		}
+		teardown()
+
+		if diff := pretty.Compare(dropped, []int{c.dropped}); diff != "" {
+			t.Errorf("OnDrop %v: invalid events: (-got +expected)\n%s", c.overflow, diff)
+		}
+		if diff := pretty.Compare(recorder.sorted(), c.sent); diff != "" {
+			t.Errorf("Overflow %v: invalid events: (-got +expected)\n%s", c.overflow, diff)
+		}
+	}
+}
+
+func TestEventTrackerBlock(t *testing.T) {
+	setup()
+	defer teardown()
+
+	block, started := make(chan struct{}), make(chan int, 10)
+	handleTrackedEvents(t, statusOK, block, started)
+
+	tracker := testClient.Events.NewTracker(&TrackerConfig{QueueSize: 1, Workers: 1})
+	tracker.Track(trackedEvent(0))
+	<-started
+	tracker.Track(trackedEvent(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := tracker.TrackWithContext(ctx, trackedEvent(2)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	close(block)
+	if err := tracker.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected error. Close: %v", err)
+	}
+}
+
+func TestEventTrackerCloseBlocked(t *testing.T) {
+	setup()
+	defer teardown()
+
+	block, started := make(chan struct{}), make(chan int, 10)
+	defer close(block)
+	handleTrackedEvents(t, statusOK, block, started)
+
+	tracker := testClient.Events.NewTracker(&TrackerConfig{QueueSize: 1, Workers: 1})
+	tracker.Track(trackedEvent(0))
+	<-started
+	tracker.Track(trackedEvent(1))
+
+	// Track waits for a free slot while the API stalls
+	tracked := make(chan error)
+	go func() {
+		tracked <- tracker.Track(trackedEvent(2))
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	closed := make(chan error)
+	go func() {
+		closed <- tracker.Close(ctx)
+	}()
+
+	select {
+	case err := <-closed:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected deadline exceeded, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Close to honour its deadline")
+	}
+	if err := <-tracked; err != ErrTrackerClosed {
+		t.Errorf("Expected ErrTrackerClosed, got %v", err)
+	}
+}
+
+func TestEventTrackerCloseTimeout(t *testing.T) {
+	setup()
+	defer teardown()
+
+	block, started := make(chan struct{}), make(chan int, 10)
+	defer close(block)
+	handleTrackedEvents(t, statusOK, block, started)
+
+	var mu sync.Mutex
+	var dropped []int
+	tracker := testClient.Events.NewTracker(&TrackerConfig{
+		Workers: 1,
+		OnDrop: func(event *Event, err error) {
+			if err != ErrTrackerClosed {
+				t.Errorf("Expected ErrTrackerClosed, got %v", err)
+			}
+			mu.Lock()
+			dropped = append(dropped, event.Properties["n"].(int))
+			mu.Unlock()
+		},
+	})
+	for n := 0; n < 3; n++ {
+		tracker.Track(trackedEvent(n))
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := tracker.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if diff := pretty.Compare(dropped, []int{0, 1, 2}); diff != "" {
+		t.Errorf("OnDrop: invalid events: (-got +expected)\n%s", diff)
+	}
+	if err := tracker.Flush(context.Background()); err != nil {
+		t.Errorf("Unexpected error. Flush: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// userAgentTransport is the RoundTripper which adds the User-Agent header on top of the user-supplied transport.
+// The Authorization header is set on the requests instead, so that http.Client strips it on redirects to other hosts
+type userAgentTransport struct {
+	userAgent string
+	base      http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") != "" {
+		return t.base.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the original request
+	uaReq := req.Clone(req.Context())
+	uaReq.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(uaReq)
+}
+
+// newHTTPClient builds the HTTP client used by the Client, layering the User-Agent on top of
+// the http.Client and http.RoundTripper provided in the config, if any
+func newHTTPClient(config *Config, userAgent string) *http.Client {
+	var httpClient http.Client
+	if config.HTTPClient != nil {
+		// Shallow copy, so that the caller's client is left untouched
+		httpClient = *config.HTTPClient
+	} else {
+		httpClient.Timeout = config.Timeout * time.Millisecond
+	}
+
+	base := config.Transport
+	if base == nil {
+		base = httpClient.Transport
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	httpClient.Transport = &userAgentTransport{userAgent: userAgent, base: base}
+	return &httpClient
+}
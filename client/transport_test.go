@@ -0,0 +1,146 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestAuthHeaders(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer fakeapikey" {
+			t.Errorf("Expected bearer token, got '%v'", auth)
+		}
+		if ua := r.Header.Get("User-Agent"); ua != userAgent {
+			t.Errorf("Expected user agent '%v', got '%v'", userAgent, ua)
+		}
+		fmt.Fprint(w, "{}")
+	})
+
+	req, _ := testClient.NewRequest(http.MethodGet, "/", nil)
+	if _, err := testClient.Do(req, nil); err != nil {
+		t.Errorf("Unexpected error. Do: %v", err)
+	}
+}
+
+func TestAuthHeaderRedirect(t *testing.T) {
+	setup()
+	defer teardown()
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Expected no credentials on the redirect to another host, got '%v'", auth)
+		}
+		fmt.Fprint(w, "{}")
+	}))
+	defer other.Close()
+
+	// Another host, as cookies and credentials are kept on redirects to the same host on another port
+	otherURL := strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, otherURL+"/elsewhere", http.StatusFound)
+	})
+
+	req, _ := testClient.NewRequest(http.MethodGet, "/", nil)
+	if _, err := testClient.Do(req, nil); err != nil {
+		t.Errorf("Unexpected error. Do: %v", err)
+	}
+}
+
+func TestCustomTransport(t *testing.T) {
+	var seen *http.Request
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		seen = req
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"id":"from-transport"}`)),
+			Request:    req,
+		}, nil
+	})
+
+	c, err := New(&Config{APIkey: "key", WorkspaceID: "ID", Transport: transport})
+	if err != nil {
+		t.Fatalf("Client New(): %v", err)
+	}
+
+	customer, err := c.Customers.Get("customer")
+	if err != nil {
+		t.Fatalf("Unexpected error. Customers.Get: %v", err)
+	}
+	if customer.ID != "from-transport" {
+		t.Errorf("Expected the response of the custom transport, got %v", customer.ID)
+	}
+	if seen == nil || seen.Header.Get("Authorization") != "Bearer key" {
+		t.Error("Expected the custom transport to receive the authenticated request")
+	}
+}
+
+func TestCustomHTTPClient(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "{}")
+	})
+
+	used := false
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			used = true
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}
+
+	c, err := New(&Config{APIkey: "key", WorkspaceID: "ID", HTTPClient: httpClient})
+	if err != nil {
+		t.Fatalf("Client New(): %v", err)
+	}
+	c.BaseURL, _ = url.Parse(mockServer.URL)
+
+	req, _ := c.NewRequest(http.MethodGet, "/", nil)
+	if _, err := c.Do(req, nil); err != nil {
+		t.Errorf("Unexpected error. Do: %v", err)
+	}
+
+	if !used {
+		t.Error("Expected the custom http.Client transport to be used")
+	}
+	if _, ok := httpClient.Transport.(*userAgentTransport); ok {
+		t.Error("Expected the caller's http.Client not to be modified")
+	}
+}
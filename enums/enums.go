@@ -33,6 +33,7 @@ type SubscriptionKind int
 type EventType int
 type BringBackPropertyType int
 type EventContext int
+type EventMode int
 
 const (
 	Mobile ContactType = iota
@@ -129,3 +130,8 @@ const (
 	Other
 	MobileCtx
 )
+
+const (
+	Active EventMode = iota
+	Passive
+)
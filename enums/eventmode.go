@@ -0,0 +1,76 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package enums
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+var (
+	_EventModeNameToValue = map[string]EventMode{
+		"ACTIVE":  Active,
+		"PASSIVE": Passive,
+	}
+
+	_EventModeValueToName = map[EventMode]string{
+		Active:  "ACTIVE",
+		Passive: "PASSIVE",
+	}
+)
+
+func init() {
+	var v EventMode
+	if _, ok := interface{}(v).(fmt.Stringer); ok {
+		_EventModeNameToValue = map[string]EventMode{
+			interface{}(Active).(fmt.Stringer).String():  Active,
+			interface{}(Passive).(fmt.Stringer).String(): Passive,
+		}
+	}
+}
+
+// MarshalJSON is generated so EventMode satisfies json.Marshaler.
+func (r EventMode) MarshalJSON() ([]byte, error) {
+	if s, ok := interface{}(r).(fmt.Stringer); ok {
+		return json.Marshal(s.String())
+	}
+	s, ok := _EventModeValueToName[r]
+	if !ok {
+		return nil, fmt.Errorf("invalid EventMode: %d", r)
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON is generated so EventMode satisfies json.Unmarshaler.
+func (r *EventMode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("EventMode should be a string, got %s", data)
+	}
+	v, ok := _EventModeNameToValue[s]
+	if !ok {
+		return fmt.Errorf("invalid EventMode %q", s)
+	}
+	*r = v
+	return nil
+}
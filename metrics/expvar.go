@@ -0,0 +1,115 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+// Package metrics provides implementations of the client.Metrics hook
+package metrics
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/contactlab/contacthub-sdk-go/client"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histogram buckets
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Expvar publishes the client metrics via the standard expvar package, as a map with
+// - calls: number of calls by service.method.statusClass
+// - latency: cumulative histogram of the call latency by service.method
+// - retries: number of retried attempts by service.method
+// - rateLimitWaits, rateLimitWaitSeconds: number and total time of the waits for the rate limiter, by service.method
+type Expvar struct {
+	mu                   sync.Mutex
+	calls                *expvar.Map
+	latency              *expvar.Map
+	retries              *expvar.Map
+	rateLimitWaits       *expvar.Map
+	rateLimitWaitSeconds *expvar.Map
+}
+
+var _ client.Metrics = (*Expvar)(nil)
+
+// NewExpvar creates and publishes the metrics with the given name.
+// As expvar.NewMap, it panics if the name is already in use
+func NewExpvar(name string) *Expvar {
+	m := &Expvar{
+		calls:                new(expvar.Map).Init(),
+		latency:              new(expvar.Map).Init(),
+		retries:              new(expvar.Map).Init(),
+		rateLimitWaits:       new(expvar.Map).Init(),
+		rateLimitWaitSeconds: new(expvar.Map).Init(),
+	}
+
+	root := expvar.NewMap(name)
+	root.Set("calls", m.calls)
+	root.Set("latency", m.latency)
+	root.Set("retries", m.retries)
+	root.Set("rateLimitWaits", m.rateLimitWaits)
+	root.Set("rateLimitWaitSeconds", m.rateLimitWaitSeconds)
+	return m
+}
+
+// ObserveCall implements the client.Metrics interface
+func (m *Expvar) ObserveCall(service, method, statusClass string, duration time.Duration) {
+	m.calls.Add(service+"."+method+"."+statusClass, 1)
+
+	seconds := duration.Seconds()
+	histogram := m.histogram(service + "." + method)
+	for _, bucket := range DefaultBuckets {
+		if seconds <= bucket {
+			histogram.Add("le_"+strconv.FormatFloat(bucket, 'f', -1, 64), 1)
+		}
+	}
+	histogram.Add("le_+Inf", 1)
+	histogram.Add("count", 1)
+	histogram.AddFloat("sum", seconds)
+}
+
+// ObserveRetry implements the client.Metrics interface
+func (m *Expvar) ObserveRetry(service, method string) {
+	m.retries.Add(service+"."+method, 1)
+}
+
+// ObserveRateLimitWait implements the client.Metrics interface
+func (m *Expvar) ObserveRateLimitWait(service, method string, wait time.Duration) {
+	m.rateLimitWaits.Add(service+"."+method, 1)
+	m.rateLimitWaitSeconds.AddFloat(service+"."+method, wait.Seconds())
+}
+
+// histogram returns the latency histogram of an operation, creating it if needed
+func (m *Expvar) histogram(key string) *expvar.Map {
+	if histogram, ok := m.latency.Get(key).(*expvar.Map); ok {
+		return histogram
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if histogram, ok := m.latency.Get(key).(*expvar.Map); ok {
+		return histogram
+	}
+	histogram := new(expvar.Map).Init()
+	m.latency.Set(key, histogram)
+	return histogram
+}
@@ -0,0 +1,80 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// runs makes the published names unique, as expvar panics on names already in use, e.g. with go test -count
+var runs int64
+
+func TestExpvar(t *testing.T) {
+	name := fmt.Sprintf("contacthub_test_%d", atomic.AddInt64(&runs, 1))
+	m := NewExpvar(name)
+	m.ObserveCall("Customers", "Get", "2xx", 200*time.Millisecond)
+	m.ObserveCall("Customers", "Get", "2xx", 3*time.Second)
+	m.ObserveCall("Customers", "Get", "4xx", time.Millisecond)
+	m.ObserveRetry("Events", "Create")
+	m.ObserveRateLimitWait("Events", "Create", 500*time.Millisecond)
+
+	var published map[string]map[string]interface{}
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &published); err != nil {
+		t.Fatalf("Invalid expvar: %v", err)
+	}
+
+	expected := map[string]map[string]interface{}{
+		"calls": {
+			"Customers.Get.2xx": 2,
+			"Customers.Get.4xx": 1,
+		},
+		"latency": {
+			"Customers.Get": map[string]interface{}{
+				"le_0.05": 1,
+				"le_0.1":  1,
+				"le_0.25": 2,
+				"le_0.5":  2,
+				"le_1":    2,
+				"le_2.5":  2,
+				"le_5":    3,
+				"le_10":   3,
+				"le_+Inf": 3,
+				"count":   3,
+				"sum":     3.201,
+			},
+		},
+		"retries":              {"Events.Create": 1},
+		"rateLimitWaits":       {"Events.Create": 1},
+		"rateLimitWaitSeconds": {"Events.Create": 0.5},
+	}
+	if diff := pretty.Compare(published, expected); diff != "" {
+		t.Errorf("Expvar: invalid values: (-got +expected)\n%s", diff)
+	}
+}
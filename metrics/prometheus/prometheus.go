@@ -0,0 +1,103 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+// Package prometheus implements the client.Metrics hook with the Prometheus client
+package prometheus
+
+import (
+	"time"
+
+	"github.com/contactlab/contacthub-sdk-go/client"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics collects the client metrics as Prometheus counters and histograms
+type Metrics struct {
+	calls                *prom.CounterVec
+	latency              *prom.HistogramVec
+	retries              *prom.CounterVec
+	rateLimitWaits       *prom.CounterVec
+	rateLimitWaitSeconds *prom.CounterVec
+}
+
+var _ client.Metrics = (*Metrics)(nil)
+
+// New creates the metrics and registers them with registerer, or with the default one if nil
+func New(registerer prom.Registerer) (*Metrics, error) {
+	if registerer == nil {
+		registerer = prom.DefaultRegisterer
+	}
+
+	labels := []string{"service", "method"}
+	m := &Metrics{
+		calls: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: "contacthub",
+			Name:      "calls_total",
+			Help:      "Number of ContactHub API calls.",
+		}, append(labels, "status_class")),
+		latency: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: "contacthub",
+			Name:      "call_duration_seconds",
+			Help:      "Latency of the ContactHub API calls, retries included.",
+			Buckets:   prom.DefBuckets,
+		}, append(labels, "status_class")),
+		retries: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: "contacthub",
+			Name:      "retries_total",
+			Help:      "Number of retried ContactHub API attempts.",
+		}, labels),
+		rateLimitWaits: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: "contacthub",
+			Name:      "rate_limit_waits_total",
+			Help:      "Number of ContactHub API calls delayed by the client rate limiter.",
+		}, labels),
+		rateLimitWaitSeconds: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: "contacthub",
+			Name:      "rate_limit_wait_seconds_total",
+			Help:      "Time spent waiting for the client rate limiter.",
+		}, labels),
+	}
+
+	for _, collector := range []prom.Collector{m.calls, m.latency, m.retries, m.rateLimitWaits, m.rateLimitWaitSeconds} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ObserveCall implements the client.Metrics interface
+func (m *Metrics) ObserveCall(service, method, statusClass string, duration time.Duration) {
+	m.calls.WithLabelValues(service, method, statusClass).Inc()
+	m.latency.WithLabelValues(service, method, statusClass).Observe(duration.Seconds())
+}
+
+// ObserveRetry implements the client.Metrics interface
+func (m *Metrics) ObserveRetry(service, method string) {
+	m.retries.WithLabelValues(service, method).Inc()
+}
+
+// ObserveRateLimitWait implements the client.Metrics interface
+func (m *Metrics) ObserveRateLimitWait(service, method string, wait time.Duration) {
+	m.rateLimitWaits.WithLabelValues(service, method).Inc()
+	m.rateLimitWaitSeconds.WithLabelValues(service, method).Add(wait.Seconds())
+}
@@ -0,0 +1,61 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := prom.NewRegistry()
+	m, err := New(registry)
+	if err != nil {
+		t.Fatalf("Unexpected error. New: %v", err)
+	}
+
+	m.ObserveCall("Customers", "Get", "2xx", 200*time.Millisecond)
+	m.ObserveCall("Customers", "Get", "2xx", 300*time.Millisecond)
+	m.ObserveRetry("Events", "Create")
+	m.ObserveRateLimitWait("Events", "Create", 500*time.Millisecond)
+
+	if value := testutil.ToFloat64(m.calls.WithLabelValues("Customers", "Get", "2xx")); value != 2 {
+		t.Errorf("Expected 2 calls, got %v", value)
+	}
+	if count := testutil.CollectAndCount(m.latency); count != 1 {
+		t.Errorf("Expected 1 latency histogram, got %v", count)
+	}
+	if value := testutil.ToFloat64(m.retries.WithLabelValues("Events", "Create")); value != 1 {
+		t.Errorf("Expected 1 retry, got %v", value)
+	}
+	if value := testutil.ToFloat64(m.rateLimitWaitSeconds.WithLabelValues("Events", "Create")); value != 0.5 {
+		t.Errorf("Expected 0.5s of rate limit wait, got %v", value)
+	}
+
+	if _, err := New(registry); err == nil {
+		t.Error("Expected error on duplicate registration.")
+	}
+}
@@ -0,0 +1,109 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+// Package tracing instruments a ContactHub client with OpenTelemetry, creating a span for every API call
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/contactlab/contacthub-sdk-go/client"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/contactlab/contacthub-sdk-go/tracing"
+
+// Span attributes specific to ContactHub
+const (
+	WorkspaceIDKey = attribute.Key("contacthub.workspace_id")
+	NodeIDKey      = attribute.Key("contacthub.node_id")
+	LogrefKey      = attribute.Key("contacthub.logref")
+)
+
+// Config contains the tracing configuration. Nil fields fall back to the global OpenTelemetry ones
+type Config struct {
+	TracerProvider trace.TracerProvider
+	Propagator     propagation.TextMapPropagator
+}
+
+// Instrument adds the tracing middleware to the client
+func Instrument(c *client.Client, config *Config) {
+	c.Use(Middleware(c, config))
+}
+
+// Middleware returns a client middleware which creates a span for every call, named after the
+// service and the operation (e.g. Customers.Update), and propagates the trace context to the API
+func Middleware(c *client.Client, config *Config) client.Middleware {
+	if config == nil {
+		config = &Config{}
+	}
+	tracerProvider := config.TracerProvider
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+	propagator := config.Propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	tracer := tracerProvider.Tracer(instrumentationName)
+
+	return func(next client.Handler) client.Handler {
+		return func(call *client.Call) (*http.Response, error) {
+			nodeID := call.Request.URL.Query().Get("nodeId")
+			if nodeID == "" {
+				nodeID = c.Config.DefaultNodeID
+			}
+
+			ctx, span := tracer.Start(call.Request.Context(), call.Service+"."+call.Operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("http.request.method", call.Method),
+					attribute.String("url.path", call.Path),
+					WorkspaceIDKey.String(c.Config.WorkspaceID),
+					NodeIDKey.String(nodeID),
+				),
+			)
+			defer span.End()
+
+			call.Request = call.Request.WithContext(ctx)
+			propagator.Inject(ctx, propagation.HeaderCarrier(call.Request.Header))
+
+			resp, err := next(call)
+
+			if resp != nil {
+				span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+			}
+			if err != nil {
+				if errorResponse, ok := err.(*client.ErrorResponse); ok {
+					span.SetAttributes(LogrefKey.String(errorResponse.Logref))
+				}
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,126 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/contactlab/contacthub-sdk-go/client"
+	"github.com/contactlab/contacthub-sdk-go/nullable"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setup(t *testing.T, handler http.HandlerFunc) (*client.Client, *tracetest.InMemoryExporter, func()) {
+	mockServer := httptest.NewServer(handler)
+
+	c, err := client.New(&client.Config{
+		DefaultNodeID: "fakenodeid",
+		WorkspaceID:   "fakeworkspaceid",
+		APIkey:        "fakeapikey",
+	})
+	if err != nil {
+		t.Fatalf("Client New(): %v", err)
+	}
+	c.BaseURL, _ = url.Parse(mockServer.URL)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	Instrument(c, &Config{TracerProvider: tracerProvider, Propagator: propagation.TraceContext{}})
+
+	return c, exporter, mockServer.Close
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	values := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestSpan(t *testing.T) {
+	c, exporter, teardown := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("traceparent") == "" {
+			t.Error("Expected the trace context to be propagated")
+		}
+		fmt.Fprint(w, `{"id":"my-customer-id"}`)
+	})
+	defer teardown()
+
+	customer := client.Customer{Enabled: nullable.BoolFrom(true)}
+	if _, err := c.Customers.Update("my-customer-id", &customer); err != nil {
+		t.Fatalf("Unexpected error. Customers.Update: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %v", len(spans))
+	}
+	if spans[0].Name != "Customers.Update" {
+		t.Errorf("Expected span Customers.Update, got %v", spans[0].Name)
+	}
+
+	values := attributes(spans[0])
+	if values["http.response.status_code"].AsInt64() != http.StatusOK {
+		t.Errorf("Unexpected status code: %v", values["http.response.status_code"].Emit())
+	}
+	if values[WorkspaceIDKey].AsString() != "fakeworkspaceid" || values[NodeIDKey].AsString() != "fakenodeid" {
+		t.Errorf("Unexpected workspace and node: %v, %v", values[WorkspaceIDKey].Emit(), values[NodeIDKey].Emit())
+	}
+}
+
+func TestSpanError(t *testing.T) {
+	c, exporter, teardown := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"customer not found","logref":"my-logref"}`)
+	})
+	defer teardown()
+
+	if _, err := c.Customers.Get("my-customer-id"); err == nil {
+		t.Fatal("Expected error.")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %v", len(spans))
+	}
+	if spans[0].Name != "Customers.Get" || spans[0].Status.Code != codes.Error {
+		t.Errorf("Unexpected span %v with status %v", spans[0].Name, spans[0].Status.Code)
+	}
+
+	values := attributes(spans[0])
+	if values[LogrefKey].AsString() != "my-logref" {
+		t.Errorf("Expected the API logref, got %v", values[LogrefKey].Emit())
+	}
+	if values["http.response.status_code"].AsInt64() != http.StatusNotFound {
+		t.Errorf("Unexpected status code: %v", values["http.response.status_code"].Emit())
+	}
+}
@@ -0,0 +1,325 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+// Package web integrates ContactHub with net/http servers: it identifies the visitors via a session cookie
+// and tracks their page views, logins and logouts as WEB events
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/contactlab/contacthub-sdk-go/client"
+	"github.com/contactlab/contacthub-sdk-go/enums"
+)
+
+const (
+	// DefaultCookieName is the default name of the session cookie
+	DefaultCookieName = "contacthub_session"
+	// DefaultCookieMaxAge is the default lifetime of the session cookie
+	DefaultCookieMaxAge = 365 * 24 * time.Hour
+)
+
+// Config configures the Tracker. Zero values are replaced with the defaults
+type Config struct {
+	CookieName   string
+	CookieMaxAge time.Duration
+	CookieDomain string
+	// CookiePath is "/" by default
+	CookiePath   string
+	CookieSecure bool
+	// CookieSameSite is http.SameSiteLaxMode by default
+	CookieSameSite http.SameSite
+	// CustomerID, if set, returns the ContactHub ID of the authenticated customer, or an empty string
+	CustomerID func(r *http.Request) string
+	// TrustProxy reads the client IP and scheme from the X-Forwarded-For and X-Forwarded-Proto headers.
+	// The client IP is the rightmost X-Forwarded-For entry not in TrustedProxies, as the leftmost entries
+	// are set by the client itself
+	TrustProxy bool
+	// TrustedProxies are the addresses of the proxies in front of the server, besides the one appending
+	// the last X-Forwarded-For entry
+	TrustedProxies []netip.Prefix
+	// EventTracker, if set, sends the events in the background instead of during the request
+	EventTracker *client.EventTracker
+}
+
+var errNoVisitor = errors.New("no visitor in the request context, the Tracker middleware is missing")
+
+// Visitor is the ContactHub identity of the visitor of a request
+type Visitor struct {
+	mu         sync.RWMutex
+	sessionID  string
+	customerID string
+}
+
+// SessionID returns the ID of the visitor session
+func (v *Visitor) SessionID() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.sessionID
+}
+
+// CustomerID returns the ID of the customer, or an empty string if the visitor is anonymous
+func (v *Visitor) CustomerID() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.customerID
+}
+
+type visitorKey struct{}
+
+// FromContext returns the Visitor stored in ctx by the Tracker middleware
+func FromContext(ctx context.Context) (*Visitor, bool) {
+	visitor, ok := ctx.Value(visitorKey{}).(*Visitor)
+	return visitor, ok
+}
+
+// SessionID returns the session ID of the visitor stored in ctx, or an empty string
+func SessionID(ctx context.Context) string {
+	if visitor, ok := FromContext(ctx); ok {
+		return visitor.SessionID()
+	}
+	return ""
+}
+
+// CustomerID returns the customer ID of the visitor stored in ctx, or an empty string
+func CustomerID(ctx context.Context) string {
+	if visitor, ok := FromContext(ctx); ok {
+		return visitor.CustomerID()
+	}
+	return ""
+}
+
+// Tracker identifies the visitors and tracks their events
+type Tracker struct {
+	client *client.Client
+	config Config
+	// anonymous binds the events of anonymous visitors to their session and reconciles them on login
+	anonymous *client.AnonymousTracker
+}
+
+// New creates a Tracker sending the events through the client
+func New(c *client.Client, config *Config) *Tracker {
+	t := &Tracker{client: c, anonymous: c.Sessions.NewAnonymousTracker(0)}
+	if config != nil {
+		t.config = *config
+	}
+	if t.config.CookieName == "" {
+		t.config.CookieName = DefaultCookieName
+	}
+	if t.config.CookieMaxAge <= 0 {
+		t.config.CookieMaxAge = DefaultCookieMaxAge
+	}
+	if t.config.CookiePath == "" {
+		t.config.CookiePath = "/"
+	}
+	if t.config.CookieSameSite == 0 {
+		t.config.CookieSameSite = http.SameSiteLaxMode
+	}
+	return t
+}
+
+// Middleware reads the session cookie, issuing a new session when missing, and stores the Visitor
+// in the request context
+func (t *Tracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		visitor := &Visitor{}
+		if cookie, err := r.Cookie(t.config.CookieName); err == nil && validSessionID(cookie.Value) {
+			visitor.sessionID = cookie.Value
+		} else {
+			visitor.sessionID = newSessionID()
+			t.setCookie(w, visitor.sessionID)
+		}
+		if t.config.CustomerID != nil {
+			visitor.customerID = t.config.CustomerID(r)
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), visitorKey{}, visitor)))
+	})
+}
+
+// ViewedPage tracks a viewedPage event for the page of the request
+func (t *Tracker) ViewedPage(r *http.Request, title string) error {
+	properties := t.page(r)
+	properties.Title = title
+	return t.track(r, enums.ViewedPage, properties)
+}
+
+// LoggedIn associates the visitor with the customer, registering the session on it so that the previous
+// anonymous events are reconciled, and tracks a loggedIn event.
+// The LinkedEvents of the result only count the anonymous events sent by this Tracker without an EventTracker
+func (t *Tracker) LoggedIn(r *http.Request, customerID string) (*client.ReconcileResult, error) {
+	visitor, ok := FromContext(r.Context())
+	if !ok {
+		return nil, errNoVisitor
+	}
+	visitor.mu.Lock()
+	visitor.customerID = customerID
+	visitor.mu.Unlock()
+
+	result, err := t.anonymous.ReconcileWithContext(r.Context(), visitor.SessionID(), customerID)
+	if err != nil {
+		return nil, err
+	}
+	return result, t.track(r, enums.LoggedIn, t.page(r))
+}
+
+// LoggedOut tracks a loggedOut event, then starts a new anonymous session
+func (t *Tracker) LoggedOut(w http.ResponseWriter, r *http.Request) error {
+	visitor, ok := FromContext(r.Context())
+	if !ok {
+		return errNoVisitor
+	}
+	err := t.track(r, enums.LoggedOut, t.page(r))
+
+	visitor.mu.Lock()
+	visitor.sessionID, visitor.customerID = newSessionID(), ""
+	visitor.mu.Unlock()
+	t.setCookie(w, visitor.SessionID())
+	return err
+}
+
+// track sends an event of the visitor, either for the customer or as an anonymous event of the session
+func (t *Tracker) track(r *http.Request, eventType enums.EventType, page client.PageProperties) error {
+	visitor, ok := FromContext(r.Context())
+	if !ok {
+		return errNoVisitor
+	}
+
+	customerID, sessionID := visitor.CustomerID(), visitor.SessionID()
+	event, err := client.NewEvent(customerID, eventType, enums.Web, page)
+	if err != nil {
+		return err
+	}
+	event.Date = &client.CustomDate{Time: time.Now()}
+
+	err = event.SetContextInfo(&client.WebContextInfo{
+		Client: &client.ClientInfo{IP: t.clientIP(r), UserAgent: r.UserAgent()},
+		Page:   &client.WebPage{URL: page.URL, Path: page.Path, Title: page.Title, Referer: page.Referer},
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case t.config.EventTracker != nil:
+		if customerID == "" {
+			if err := t.anonymous.Prepare(sessionID, event); err != nil {
+				return err
+			}
+		}
+		// With OverflowBlock, the wait for a free slot must not outlive the request
+		return t.config.EventTracker.TrackWithContext(r.Context(), event)
+	case customerID == "":
+		_, err = t.anonymous.TrackWithContext(r.Context(), sessionID, event)
+	default:
+		_, err = t.client.Events.CreateWithContext(r.Context(), event)
+	}
+	return err
+}
+
+// page returns the properties of the page of the request
+func (t *Tracker) page(r *http.Request) client.PageProperties {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if t.config.TrustProxy {
+		// The first proxy of a chain may list the schemes of all the hops, e.g. "https, http"
+		proto, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Proto"), ",")
+		if proto = strings.ToLower(strings.TrimSpace(proto)); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+	}
+	return client.PageProperties{
+		URL:     scheme + "://" + r.Host + r.URL.RequestURI(),
+		Path:    r.URL.Path,
+		Referer: r.Referer(),
+	}
+}
+
+// clientIP returns the IP of the visitor, from the X-Forwarded-For entries when behind a trusted proxy
+func (t *Tracker) clientIP(r *http.Request) string {
+	if forwarded := r.Header.Values("X-Forwarded-For"); t.config.TrustProxy && len(forwarded) > 0 {
+		entries := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(entries) - 1; i >= 0; i-- {
+			ip, err := netip.ParseAddr(strings.TrimSpace(entries[i]))
+			if err != nil {
+				break
+			}
+			if !t.trustedProxy(ip) {
+				return ip.String()
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil || net.ParseIP(host) == nil {
+		return ""
+	}
+	return host
+}
+
+func (t *Tracker) trustedProxy(ip netip.Addr) bool {
+	for _, prefix := range t.config.TrustedProxies {
+		if prefix.Contains(ip.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Tracker) setCookie(w http.ResponseWriter, sessionID string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     t.config.CookieName,
+		Value:    sessionID,
+		Path:     t.config.CookiePath,
+		Domain:   t.config.CookieDomain,
+		MaxAge:   int(t.config.CookieMaxAge / time.Second),
+		Secure:   t.config.CookieSecure,
+		HttpOnly: true,
+		SameSite: t.config.CookieSameSite,
+	})
+}
+
+// newSessionID returns a random session ID of 32 hex characters
+func newSessionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validSessionID checks the session IDs read from the cookie, which is controlled by the visitor
+func validSessionID(sessionID string) bool {
+	if len(sessionID) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(sessionID)
+	return err == nil
+}
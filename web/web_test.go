@@ -0,0 +1,341 @@
+/**
+ * This file is part of contacthub-sdk-go.
+ *
+ * contacthub-sdk-go is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 2 of the License, or
+ * (at your option) any later version.
+ *
+ * contacthub-sdk-go is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with contacthub-sdk-go. If not, see <http://www.gnu.org/licenses/>.
+ *
+ * Copyright (C) 2017 Arduino AG
+ *
+ * @author Luca Osti
+ *
+ */
+
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contactlab/contacthub-sdk-go/client"
+	"github.com/contactlab/contacthub-sdk-go/enums"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+// apiRecorder records the events and the sessions received by the mock API
+type apiRecorder struct {
+	mu       sync.Mutex
+	events   []client.Event
+	sessions map[string][]string
+}
+
+func setup(t *testing.T) (*client.Client, *apiRecorder, func()) {
+	recorder := &apiRecorder{sessions: map[string][]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		event := client.Event{}
+		json.NewDecoder(r.Body).Decode(&event)
+		recorder.mu.Lock()
+		recorder.events = append(recorder.events, event)
+		recorder.mu.Unlock()
+		fmt.Fprint(w, `{"id":"my-event-id"}`)
+	})
+	mux.HandleFunc("/customers/my-customer-id/sessions", func(w http.ResponseWriter, r *http.Request) {
+		session := client.Session{}
+		json.NewDecoder(r.Body).Decode(&session)
+		recorder.mu.Lock()
+		recorder.sessions["my-customer-id"] = append(recorder.sessions["my-customer-id"], session.Value)
+		recorder.mu.Unlock()
+		fmt.Fprintf(w, `{"id":"my-session","value":"%s"}`, session.Value)
+	})
+	mockServer := httptest.NewServer(mux)
+
+	c, err := client.New(&client.Config{
+		DefaultNodeID: "fakenodeid",
+		WorkspaceID:   "fakeworkspaceid",
+		APIkey:        "fakeapikey",
+	})
+	if err != nil {
+		t.Fatalf("Client New(): %v", err)
+	}
+	c.BaseURL, _ = url.Parse(mockServer.URL)
+	return c, recorder, mockServer.Close
+}
+
+// serve runs handler behind the Tracker middleware, returning the response
+func serve(tracker *Tracker, r *http.Request, handler http.HandlerFunc) *http.Response {
+	w := httptest.NewRecorder()
+	tracker.Middleware(handler).ServeHTTP(w, r)
+	return w.Result()
+}
+
+func sessionCookie(resp *http.Response) *http.Cookie {
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == DefaultCookieName {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestMiddlewareSession(t *testing.T) {
+	c, _, teardown := setup(t)
+	defer teardown()
+
+	tracker := New(c, nil)
+
+	var sessionID string
+	resp := serve(tracker, httptest.NewRequest(http.MethodGet, "/", nil), func(w http.ResponseWriter, r *http.Request) {
+		sessionID = SessionID(r.Context())
+	})
+	cookie := sessionCookie(resp)
+	if cookie == nil || cookie.Value != sessionID || !validSessionID(sessionID) || !cookie.HttpOnly {
+		t.Fatalf("Expected a new session cookie for %v, got %+v", sessionID, cookie)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookie)
+	resp = serve(tracker, r, func(w http.ResponseWriter, r *http.Request) {
+		if SessionID(r.Context()) != sessionID {
+			t.Errorf("Expected session %v, got %v", sessionID, SessionID(r.Context()))
+		}
+	})
+	if sessionCookie(resp) != nil {
+		t.Error("Expected no new cookie for an existing session")
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "forged"})
+	resp = serve(tracker, r, func(w http.ResponseWriter, r *http.Request) {})
+	if cookie := sessionCookie(resp); cookie == nil || cookie.Value == "forged" {
+		t.Errorf("Expected a new session for an invalid cookie, got %+v", cookie)
+	}
+}
+
+func TestViewedPage(t *testing.T) {
+	c, recorder, teardown := setup(t)
+	defer teardown()
+
+	tracker := New(c, &Config{TrustProxy: true, TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}})
+	r := httptest.NewRequest(http.MethodGet, "/products?id=1", nil)
+	r.Header.Set("User-Agent", "Somebrowser/1.1")
+	r.Header.Set("Referer", "https://search.example/")
+	r.Header.Set("X-Forwarded-For", "111.111.111.111, 10.0.0.1")
+	r.Header.Set("X-Forwarded-Proto", "https")
+
+	var sessionID string
+	serve(tracker, r, func(w http.ResponseWriter, r *http.Request) {
+		sessionID = SessionID(r.Context())
+		if err := tracker.ViewedPage(r, "Products"); err != nil {
+			t.Errorf("Unexpected error. ViewedPage: %v", err)
+		}
+	})
+
+	if len(recorder.events) != 1 {
+		t.Fatalf("Expected 1 event, got %v", len(recorder.events))
+	}
+	event := recorder.events[0]
+	if event.Type != enums.ViewedPage || event.Context != enums.Web || event.CustomerID != nil || event.Date == nil {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	expectedBringBack := &client.BringBackProperty{Type: enums.SessionId, Value: sessionID, NodeID: "fakenodeid"}
+	if diff := pretty.Compare(event.BringBackProperty, expectedBringBack); diff != "" {
+		t.Errorf("ViewedPage: invalid bring back property: (-got +expected)\n%s", diff)
+	}
+	expectedProperties := map[string]interface{}{
+		"title":   "Products",
+		"url":     "https://example.com/products?id=1",
+		"path":    "/products",
+		"referer": "https://search.example/",
+	}
+	if diff := pretty.Compare(event.Properties, expectedProperties); diff != "" {
+		t.Errorf("ViewedPage: invalid properties: (-got +expected)\n%s", diff)
+	}
+	expectedContextInfo := &map[string]interface{}{
+		"client": map[string]interface{}{"ip": "111.111.111.111", "userAgent": "Somebrowser/1.1"},
+		"page":   expectedProperties,
+	}
+	if diff := pretty.Compare(event.ContextInfo, expectedContextInfo); diff != "" {
+		t.Errorf("ViewedPage: invalid context info: (-got +expected)\n%s", diff)
+	}
+}
+
+func TestLoginLogout(t *testing.T) {
+	c, recorder, teardown := setup(t)
+	defer teardown()
+
+	tracker := New(c, nil)
+	var sessionID string
+	resp := serve(tracker, httptest.NewRequest(http.MethodPost, "/login", nil), func(w http.ResponseWriter, r *http.Request) {
+		sessionID = SessionID(r.Context())
+		if err := tracker.ViewedPage(r, ""); err != nil {
+			t.Errorf("Unexpected error. ViewedPage: %v", err)
+		}
+		result, err := tracker.LoggedIn(r, "my-customer-id")
+		if err != nil {
+			t.Fatalf("Unexpected error. LoggedIn: %v", err)
+		}
+		if result.LinkedEvents != 1 || result.Session.Value != sessionID {
+			t.Errorf("Expected the anonymous page view to be linked, got %+v", result)
+		}
+		if CustomerID(r.Context()) != "my-customer-id" {
+			t.Errorf("Expected the customer ID in the context, got %v", CustomerID(r.Context()))
+		}
+	})
+
+	if diff := pretty.Compare(recorder.sessions["my-customer-id"], []string{sessionID}); diff != "" {
+		t.Errorf("LoggedIn: invalid sessions: (-got +expected)\n%s", diff)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	r.AddCookie(sessionCookie(resp))
+	tracker.config.CustomerID = func(r *http.Request) string { return "my-customer-id" }
+	resp = serve(tracker, r, func(w http.ResponseWriter, r *http.Request) {
+		if err := tracker.LoggedOut(w, r); err != nil {
+			t.Errorf("Unexpected error. LoggedOut: %v", err)
+		}
+		if CustomerID(r.Context()) != "" || SessionID(r.Context()) == sessionID {
+			t.Error("Expected a new anonymous session after the logout")
+		}
+	})
+	if cookie := sessionCookie(resp); cookie == nil || cookie.Value == sessionID {
+		t.Errorf("Expected a new session cookie, got %+v", cookie)
+	}
+
+	if len(recorder.events) != 3 {
+		t.Fatalf("Expected 3 events, got %v", len(recorder.events))
+	}
+	if event := recorder.events[0]; event.Type != enums.ViewedPage || event.BringBackProperty == nil {
+		t.Errorf("Expected an anonymous viewedPage event, got %+v", event)
+	}
+	for i, eventType := range []enums.EventType{enums.LoggedIn, enums.LoggedOut} {
+		event := recorder.events[i+1]
+		if event.Type != eventType || event.CustomerID == nil || event.CustomerID.String != "my-customer-id" || event.BringBackProperty != nil {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	}
+}
+
+func TestEventTrackerRequestContext(t *testing.T) {
+	c, _, teardown := setup(t)
+	defer teardown()
+
+	// The API stalls, so the queue of the EventTracker stays full
+	block := make(chan struct{})
+	stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer stalled.Close()
+	defer close(block)
+	c.BaseURL, _ = url.Parse(stalled.URL)
+
+	events := c.Events.NewTracker(&client.TrackerConfig{QueueSize: 1, Workers: 1})
+	defer func() {
+		// Give up on the stalled events
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		events.Close(ctx)
+	}()
+	tracker := New(c, &Config{EventTracker: events})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		serve(tracker, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx), func(w http.ResponseWriter, r *http.Request) {
+			for i := 0; i < 3; i++ {
+				tracker.ViewedPage(r, "")
+			}
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the tracking to give up with the request context")
+	}
+}
+
+func TestPageScheme(t *testing.T) {
+	cases := []struct {
+		config   Config
+		proto    string
+		expected string
+	}{
+		{Config{}, "https", "http://example.com/"},
+		{Config{TrustProxy: true}, "https", "https://example.com/"},
+		{Config{TrustProxy: true}, "HTTPS, http", "https://example.com/"},
+		{Config{TrustProxy: true}, "javascript", "http://example.com/"},
+		{Config{TrustProxy: true}, "", "http://example.com/"},
+	}
+
+	for _, c := range cases {
+		tracker := &Tracker{config: c.config}
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Forwarded-Proto", c.proto)
+		if url := tracker.page(r).URL; url != c.expected {
+			t.Errorf("Expected %v for %q, got %v", c.expected, c.proto, url)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	cases := []struct {
+		config    Config
+		forwarded []string
+		expected  string
+	}{
+		{Config{}, []string{"111.111.111.111"}, "192.0.2.1"},
+		// The leftmost entries are set by the client
+		{Config{TrustProxy: true}, []string{"6.6.6.6, 111.111.111.111"}, "111.111.111.111"},
+		{Config{TrustProxy: true}, []string{"6.6.6.6", "111.111.111.111"}, "111.111.111.111"},
+		{
+			Config{TrustProxy: true, TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}},
+			[]string{"6.6.6.6, 111.111.111.111, 10.0.0.2, 10.0.0.1"},
+			"111.111.111.111",
+		},
+		{Config{TrustProxy: true}, []string{"not-an-ip"}, "192.0.2.1"},
+	}
+
+	for _, c := range cases {
+		tracker := &Tracker{config: c.config}
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		for _, forwarded := range c.forwarded {
+			r.Header.Add("X-Forwarded-For", forwarded)
+		}
+		if ip := tracker.clientIP(r); ip != c.expected {
+			t.Errorf("Expected %v for %v, got %v", c.expected, c.forwarded, ip)
+		}
+	}
+}
+
+func TestWithoutMiddleware(t *testing.T) {
+	c, _, teardown := setup(t)
+	defer teardown()
+
+	tracker := New(c, nil)
+	if err := tracker.ViewedPage(httptest.NewRequest(http.MethodGet, "/", nil), ""); err != errNoVisitor {
+		t.Errorf("Expected errNoVisitor, got %v", err)
+	}
+}